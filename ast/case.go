@@ -0,0 +1,71 @@
+package ast
+
+import (
+	"github.com/Konsultn-Engineering/enorm/utils"
+	"hash/fnv"
+)
+
+// When is a single WHEN ... THEN ... branch of a CASE expression.
+type When struct {
+	Cond   Node
+	Result Node
+}
+
+// CaseExpr renders CASE [operand] WHEN ... THEN ... [ELSE ...] END.
+// With an Operand each Cond is compared to it ("simple" CASE); without one
+// each Cond is a boolean expression ("searched" CASE).
+type CaseExpr struct {
+	Operand Node
+	Whens   []When
+	Else    Node
+}
+
+func NewCaseExpr(operand Node) *CaseExpr {
+	return &CaseExpr{Operand: operand}
+}
+
+func (c *CaseExpr) Type() NodeType         { return NodeCase }
+func (c *CaseExpr) Accept(v Visitor) error { return v.VisitCaseExpr(c) }
+func (c *CaseExpr) Fingerprint() uint64 {
+	h := fnv.New64a()
+	h.Write([]byte("case:"))
+	if c.Operand != nil {
+		h.Write(utils.U64ToBytes(c.Operand.Fingerprint()))
+	}
+	for _, w := range c.Whens {
+		h.Write([]byte("when:"))
+		h.Write(utils.U64ToBytes(w.Cond.Fingerprint()))
+		h.Write(utils.U64ToBytes(w.Result.Fingerprint()))
+	}
+	if c.Else != nil {
+		h.Write([]byte("else:"))
+		h.Write(utils.U64ToBytes(c.Else.Fingerprint()))
+	}
+	return h.Sum64()
+}
+
+// AddWhen appends a WHEN cond THEN result branch.
+func (c *CaseExpr) AddWhen(cond, result Node) {
+	c.Whens = append(c.Whens, When{Cond: cond, Result: result})
+}
+
+func (c *CaseExpr) Release() {
+	release := func(n Node) {
+		if releasable, ok := n.(interface{ Release() }); ok {
+			releasable.Release()
+		}
+	}
+	if c.Operand != nil {
+		release(c.Operand)
+	}
+	for _, w := range c.Whens {
+		release(w.Cond)
+		release(w.Result)
+	}
+	if c.Else != nil {
+		release(c.Else)
+	}
+	c.Operand = nil
+	c.Whens = nil
+	c.Else = nil
+}
@@ -1,9 +1,50 @@
 package ast
 
+import (
+	"github.com/Konsultn-Engineering/enorm/utils"
+	"hash/fnv"
+)
+
 type DeleteStmt struct {
 	Table *Table
 	Where *WhereClause
 }
 
+func NewDeleteStmt(table *Table) *DeleteStmt {
+	return &DeleteStmt{Table: table}
+}
+
 func (d *DeleteStmt) Type() NodeType         { return NodeDelete }
 func (d *DeleteStmt) Accept(v Visitor) error { return v.VisitDelete(d) }
+func (d *DeleteStmt) Fingerprint() uint64 {
+	h := fnv.New64a()
+	h.Write([]byte("delete:"))
+	if d.Table != nil {
+		h.Write(utils.U64ToBytes(d.Table.Fingerprint()))
+	}
+	if d.Where != nil {
+		h.Write(utils.U64ToBytes(d.Where.Fingerprint()))
+	}
+	return h.Sum64()
+}
+
+func (d *DeleteStmt) AddWhereCondition(condition Node, operator string) {
+	d.Where = appendWhereCondition(d.Where, condition, operator)
+}
+
+func (d *DeleteStmt) Release() {
+	if d.Table != nil {
+		d.Table.Release()
+	}
+	if d.Where != nil {
+		d.Where.Release()
+	}
+	d.Table = nil
+	d.Where = nil
+}
+
+// AddScopeCondition ANDs condition onto the WHERE clause as a whole, grouping
+// any existing OR conditions so the scope cannot be bypassed.
+func (d *DeleteStmt) AddScopeCondition(condition Node) {
+	d.Where = scopeWhere(d.Where, condition)
+}
@@ -18,3 +18,10 @@ func (g *GroupedExpr) Fingerprint() uint64 {
 	}
 	return g.Expr.Fingerprint()
 }
+
+func (g *GroupedExpr) Release() {
+	if releasable, ok := g.Expr.(interface{ Release() }); ok {
+		releasable.Release()
+	}
+	g.Expr = nil
+}
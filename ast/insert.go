@@ -1,10 +1,89 @@
 package ast
 
+import (
+	"github.com/Konsultn-Engineering/enorm/utils"
+	"hash/fnv"
+	"strconv"
+)
+
 type InsertStmt struct {
-	Table   *Table
-	Columns []string
-	Values  [][]Node
+	Table      *Table
+	Columns    []string
+	Values     [][]Node // One slice of value nodes per row, aligned with Columns
+	OnConflict *OnConflictClause
+	Returning  []string
+}
+
+// OnConflictClause describes how an INSERT resolves unique key conflicts.
+// Dialects render it as ON CONFLICT (PostgreSQL) or ON DUPLICATE KEY UPDATE (MySQL/TiDB).
+type OnConflictClause struct {
+	Columns    []string     // Conflict target columns
+	Constraint string       // Named constraint target (PostgreSQL only); overrides Columns
+	DoNothing  bool         // Skip conflicting rows
+	Update     []string     // Columns overwritten with the proposed row's value
+	Where      *WhereClause // Condition on DO UPDATE (PostgreSQL only)
+}
+
+func NewInsertStmt(table *Table, columns ...string) *InsertStmt {
+	return &InsertStmt{Table: table, Columns: columns}
 }
 
 func (i *InsertStmt) Type() NodeType         { return NodeInsert }
 func (i *InsertStmt) Accept(v Visitor) error { return v.VisitInsert(i) }
+func (i *InsertStmt) Fingerprint() uint64 {
+	h := fnv.New64a()
+	h.Write([]byte("insert:"))
+	if i.Table != nil {
+		h.Write(utils.U64ToBytes(i.Table.Fingerprint()))
+	}
+	for _, col := range i.Columns {
+		h.Write([]byte(col + ","))
+	}
+	for _, row := range i.Values {
+		h.Write([]byte("row:" + strconv.Itoa(len(row))))
+		for _, val := range row {
+			h.Write(utils.U64ToBytes(val.Fingerprint()))
+		}
+	}
+	if c := i.OnConflict; c != nil {
+		h.Write([]byte("conflict:" + c.Constraint + ":" + strconv.FormatBool(c.DoNothing)))
+		for _, col := range c.Columns {
+			h.Write([]byte(col + ","))
+		}
+		h.Write([]byte("update:"))
+		for _, col := range c.Update {
+			h.Write([]byte(col + ","))
+		}
+		if c.Where != nil {
+			h.Write(utils.U64ToBytes(c.Where.Fingerprint()))
+		}
+	}
+	h.Write([]byte("returning:"))
+	for _, col := range i.Returning {
+		h.Write([]byte(col + ","))
+	}
+	return h.Sum64()
+}
+
+// AddRow appends one row of values, aligned with Columns.
+func (i *InsertStmt) AddRow(values ...Node) {
+	i.Values = append(i.Values, values)
+}
+
+func (i *InsertStmt) Release() {
+	if i.Table != nil {
+		i.Table.Release()
+	}
+	for _, row := range i.Values {
+		for _, val := range row {
+			if releasable, ok := val.(interface{ Release() }); ok {
+				releasable.Release()
+			}
+		}
+	}
+	i.Table = nil
+	i.Columns = nil
+	i.Values = nil
+	i.OnConflict = nil
+	i.Returning = nil
+}
@@ -22,6 +22,9 @@ const (
 	NodeGroupBy
 	NodeOrderBy
 	NodeLimit
+	NodeTuple
+	NodeValuesTable
+	NodeCase
 )
 
 type Node interface {
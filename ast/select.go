@@ -59,9 +59,9 @@ func (s *SelectStmt) Fingerprint() uint64 {
 	for _, col := range s.Columns {
 		h.Write(utils.U64ToBytes(col.Fingerprint()))
 	}
-	//for _, j := range s.Joins {
-	//	h.Write(utils.U64ToBytes(j.Fingerprint()))
-	//}
+	for _, j := range s.Joins {
+		h.Write(utils.U64ToBytes(j.Fingerprint()))
+	}
 
 	if s.Where != nil {
 		h.Write(utils.U64ToBytes(s.Where.Fingerprint()))
@@ -84,17 +84,7 @@ func (s *SelectStmt) Fingerprint() uint64 {
 	return h.Sum64()
 }
 func (s *SelectStmt) AddWhereCondition(condition Node, operator string) {
-	newCondition := NewWhereClause(condition, operator)
-
-	if s.Where == nil {
-		s.Where = &WhereClause{
-			First: newCondition,
-			Tail:  newCondition,
-		}
-	} else {
-		s.Where.Tail.Next = newCondition
-		s.Where.Tail = newCondition
-	}
+	s.Where = appendWhereCondition(s.Where, condition, operator)
 }
 func (s *SelectStmt) AddOrderByClause(table string, desc bool, columns ...string) {
 	if len(columns) == 0 {
@@ -230,3 +220,9 @@ func (s *SelectStmt) Release() {
 
 	selectStmtPool.Put(s)
 }
+
+// AddScopeCondition ANDs condition onto the WHERE clause as a whole, grouping
+// any existing OR conditions so the scope cannot be bypassed.
+func (s *SelectStmt) AddScopeCondition(condition Node) {
+	s.Where = scopeWhere(s.Where, condition)
+}
@@ -0,0 +1,36 @@
+package ast
+
+import (
+	"github.com/Konsultn-Engineering/enorm/utils"
+	"hash/fnv"
+)
+
+// Tuple is a parenthesised list of expressions, e.g. ("a", "b") or ($1, $2).
+// Used for row-value comparisons such as composite primary key lookups.
+type Tuple struct {
+	Items []Node
+}
+
+func NewTuple(items ...Node) *Tuple {
+	return &Tuple{Items: items}
+}
+
+func (t *Tuple) Type() NodeType         { return NodeTuple }
+func (t *Tuple) Accept(v Visitor) error { return v.VisitTuple(t) }
+func (t *Tuple) Fingerprint() uint64 {
+	h := fnv.New64a()
+	h.Write([]byte("tuple:"))
+	for _, item := range t.Items {
+		h.Write(utils.U64ToBytes(item.Fingerprint()))
+	}
+	return h.Sum64()
+}
+
+func (t *Tuple) Release() {
+	for _, item := range t.Items {
+		if releasable, ok := item.(interface{ Release() }); ok {
+			releasable.Release()
+		}
+	}
+	t.Items = nil
+}
@@ -1,10 +1,83 @@
 package ast
 
+import (
+	"github.com/Konsultn-Engineering/enorm/utils"
+	"hash/fnv"
+)
+
+// Assignment is a single "column = value" pair in an UPDATE SET list.
+type Assignment struct {
+	Column string
+	Value  Node
+}
+
 type UpdateStmt struct {
 	Table *Table
-	Set   map[string]Node
+	Set   []Assignment // Ordered so rendered SQL and args are deterministic
+	From  Node         // Optional additional source (e.g., a ValuesTable); PostgreSQL only
 	Where *WhereClause
 }
 
+func NewUpdateStmt(table *Table) *UpdateStmt {
+	return &UpdateStmt{Table: table}
+}
+
 func (u *UpdateStmt) Type() NodeType         { return NodeUpdate }
 func (u *UpdateStmt) Accept(v Visitor) error { return v.VisitUpdate(u) }
+func (u *UpdateStmt) Fingerprint() uint64 {
+	h := fnv.New64a()
+	h.Write([]byte("update:"))
+	if u.Table != nil {
+		h.Write(utils.U64ToBytes(u.Table.Fingerprint()))
+	}
+	for _, a := range u.Set {
+		h.Write([]byte(a.Column + "="))
+		if a.Value != nil {
+			h.Write(utils.U64ToBytes(a.Value.Fingerprint()))
+		}
+	}
+	if u.From != nil {
+		h.Write([]byte("from:"))
+		h.Write(utils.U64ToBytes(u.From.Fingerprint()))
+	}
+	if u.Where != nil {
+		h.Write(utils.U64ToBytes(u.Where.Fingerprint()))
+	}
+	return h.Sum64()
+}
+
+// AddSet appends a "column = value" assignment.
+func (u *UpdateStmt) AddSet(column string, value Node) {
+	u.Set = append(u.Set, Assignment{Column: column, Value: value})
+}
+
+func (u *UpdateStmt) AddWhereCondition(condition Node, operator string) {
+	u.Where = appendWhereCondition(u.Where, condition, operator)
+}
+
+func (u *UpdateStmt) Release() {
+	if u.Table != nil {
+		u.Table.Release()
+	}
+	for _, a := range u.Set {
+		if releasable, ok := a.Value.(interface{ Release() }); ok {
+			releasable.Release()
+		}
+	}
+	if releasable, ok := u.From.(interface{ Release() }); ok {
+		releasable.Release()
+	}
+	if u.Where != nil {
+		u.Where.Release()
+	}
+	u.Table = nil
+	u.Set = nil
+	u.From = nil
+	u.Where = nil
+}
+
+// AddScopeCondition ANDs condition onto the WHERE clause as a whole, grouping
+// any existing OR conditions so the scope cannot be bypassed.
+func (u *UpdateStmt) AddScopeCondition(condition Node) {
+	u.Where = scopeWhere(u.Where, condition)
+}
@@ -0,0 +1,61 @@
+package ast
+
+import (
+	"github.com/Konsultn-Engineering/enorm/utils"
+	"hash/fnv"
+)
+
+// ValuesTable is an inline VALUES list used as a table source:
+//
+//	(VALUES ($1, $2), ($3, $4)) AS "v"("id", "name")
+//
+// Types, when set, are cast on the first row so the database infers each
+// column's type instead of treating bind parameters as text.
+type ValuesTable struct {
+	Alias   string
+	Columns []string
+	Types   []string
+	Rows    [][]Node
+}
+
+func NewValuesTable(alias string, columns ...string) *ValuesTable {
+	return &ValuesTable{Alias: alias, Columns: columns}
+}
+
+func (t *ValuesTable) Type() NodeType         { return NodeValuesTable }
+func (t *ValuesTable) Accept(v Visitor) error { return v.VisitValuesTable(t) }
+func (t *ValuesTable) Fingerprint() uint64 {
+	h := fnv.New64a()
+	h.Write([]byte("values:" + t.Alias + ":"))
+	for _, col := range t.Columns {
+		h.Write([]byte(col + ","))
+	}
+	for _, typ := range t.Types {
+		h.Write([]byte(typ + ","))
+	}
+	for _, row := range t.Rows {
+		h.Write([]byte("("))
+		for _, n := range row {
+			h.Write(utils.U64ToBytes(n.Fingerprint()))
+		}
+	}
+	return h.Sum64()
+}
+
+// AddRow appends one row; values must match Columns in length and order.
+func (t *ValuesTable) AddRow(values ...Node) {
+	t.Rows = append(t.Rows, values)
+}
+
+func (t *ValuesTable) Release() {
+	for _, row := range t.Rows {
+		for _, n := range row {
+			if releasable, ok := n.(interface{ Release() }); ok {
+				releasable.Release()
+			}
+		}
+	}
+	t.Rows = nil
+	t.Columns = nil
+	t.Types = nil
+}
@@ -16,6 +16,9 @@ type Visitor interface {
 	VisitBinaryExpr(*BinaryExpr) error
 	VisitUnaryExpr(*UnaryExpr) error
 	VisitSubqueryExpr(*SubqueryExpr) error
+	VisitTuple(*Tuple) error
+	VisitValuesTable(*ValuesTable) error
+	VisitCaseExpr(*CaseExpr) error
 
 	VisitWhereClause(*WhereClause) error
 	VisitJoinClause(*JoinClause) error
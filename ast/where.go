@@ -21,6 +21,36 @@ func NewWhereClause(condition Node, operator string) *WhereCondition {
 	return wc
 }
 
+// appendWhereCondition appends a condition to w, allocating the clause on first use.
+func appendWhereCondition(w *WhereClause, condition Node, operator string) *WhereClause {
+	newCondition := NewWhereClause(condition, operator)
+	if w == nil {
+		return &WhereClause{First: newCondition, Tail: newCondition}
+	}
+	w.Tail.Next = newCondition
+	w.Tail = newCondition
+	return w
+}
+
+// scopeWhere ANDs condition onto w so that it restricts the clause as a whole.
+// If w contains OR, the existing conditions are wrapped in parentheses first:
+//
+//	a = 1 OR b = 2  ->  (a = 1 OR b = 2) AND condition
+func scopeWhere(w *WhereClause, condition Node) *WhereClause {
+	if w == nil || w.First == nil {
+		return appendWhereCondition(nil, condition, OpAnd)
+	}
+
+	for c := w.First.Next; c != nil; c = c.Next {
+		if c.Operator == OpOr {
+			grouped := NewWhereClause(&GroupedExpr{Expr: w}, OpAnd)
+			w = &WhereClause{First: grouped, Tail: grouped}
+			break
+		}
+	}
+	return appendWhereCondition(w, condition, OpAnd)
+}
+
 func (w *WhereClause) Type() NodeType         { return NodeWhere }
 func (w *WhereClause) Accept(v Visitor) error { return v.VisitWhereClause(w) }
 func (w *WhereClause) Fingerprint() uint64 {
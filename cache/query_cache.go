@@ -2,6 +2,7 @@ package cache
 
 import (
 	"sync"
+	"sync/atomic"
 )
 
 type CachedQuery struct {
@@ -15,12 +16,31 @@ type CachedQuery struct {
 type QueryCache interface {
 	Get(fingerprint uint64) (*CachedQuery, bool)
 	Set(fingerprint uint64, sql string, args []any, argsOrder []string, stmtKey string, scannerID string)
+	Stats() QueryCacheStats
+}
+
+// QueryCacheStats reports query cache usage since the cache was created.
+type QueryCacheStats struct {
+	Hits    uint64
+	Misses  uint64
+	Entries int
+}
+
+// HitRatio returns the share of lookups served from the cache, or 0 before
+// the first lookup.
+func (s QueryCacheStats) HitRatio() float64 {
+	if total := s.Hits + s.Misses; total > 0 {
+		return float64(s.Hits) / float64(total)
+	}
+	return 0
 }
 
 type memQueryCache struct {
 	mu             sync.RWMutex
 	data           map[uint64]*CachedQuery
 	queryCachePool sync.Pool
+	hits           atomic.Uint64
+	misses         atomic.Uint64
 }
 
 func NewQueryCache() QueryCache {
@@ -38,9 +58,21 @@ func (c *memQueryCache) Get(f uint64) (*CachedQuery, bool) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 	q, ok := c.data[f]
+	if ok {
+		c.hits.Add(1)
+	} else {
+		c.misses.Add(1)
+	}
 	return q, ok
 }
 
+func (c *memQueryCache) Stats() QueryCacheStats {
+	c.mu.RLock()
+	entries := len(c.data)
+	c.mu.RUnlock()
+	return QueryCacheStats{Hits: c.hits.Load(), Misses: c.misses.Load(), Entries: entries}
+}
+
 func (c *memQueryCache) Set(f uint64, sql string, args []any, argsOrder []string, stmtKey string, scannerID string) {
 	q := c.queryCachePool.Get().(*CachedQuery)
 	if q == nil {
@@ -0,0 +1,119 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Replica is a healthy replica offered to a ReadBalancer.
+type Replica struct {
+	Name    string // "replica_N", N being its index in ClusterConfig.Replicas
+	Conn    Connection
+	Weight  int           // Config.Weight, at least 1
+	Latency time.Duration // Rolling ping latency from the health monitor, 0 before the first check
+	Lag     time.Duration // Replication lag at the last health check
+}
+
+// ReadBalancer chooses the replica a read goes to. Pick is only called with
+// at least one replica, and must be safe for concurrent use. A nil
+// Connection sends the read to the primary.
+type ReadBalancer interface {
+	Pick(ctx context.Context, replicas []Replica) Connection
+}
+
+// NewReadBalancer returns the balancer for a ClusterConfig.ReadStrategy:
+// "round_robin", "random", "closest", "least_connections" or
+// "weighted_round_robin". "primary" and "" return nil: reads go to the
+// primary.
+func NewReadBalancer(strategy string) (ReadBalancer, error) {
+	switch strategy {
+	case "primary", "":
+		return nil, nil
+	case "round_robin":
+		return &RoundRobin{}, nil
+	case "random":
+		return Random{}, nil
+	case "closest":
+		return Closest{}, nil
+	case "least_connections":
+		return LeastConnections{}, nil
+	case "weighted_round_robin":
+		return &WeightedRoundRobin{}, nil
+	default:
+		return nil, fmt.Errorf("invalid read strategy: %s", strategy)
+	}
+}
+
+// RoundRobin cycles through the replicas.
+type RoundRobin struct {
+	next atomic.Uint64
+}
+
+func (b *RoundRobin) Pick(_ context.Context, replicas []Replica) Connection {
+	return replicas[(b.next.Add(1)-1)%uint64(len(replicas))].Conn
+}
+
+// Random picks a replica uniformly at random.
+type Random struct{}
+
+func (Random) Pick(_ context.Context, replicas []Replica) Connection {
+	return replicas[rand.Intn(len(replicas))].Conn
+}
+
+// Closest picks the replica with the lowest ping latency, as measured by the
+// health monitor (see PoolConfig.HealthCheckFreq). Replicas not yet
+// measured are only picked when none are.
+type Closest struct{}
+
+func (Closest) Pick(_ context.Context, replicas []Replica) Connection {
+	best := replicas[0]
+	for _, r := range replicas[1:] {
+		if r.Latency > 0 && (best.Latency == 0 || r.Latency < best.Latency) {
+			best = r
+		}
+	}
+	return best.Conn
+}
+
+// LeastConnections picks the replica with the fewest connections in use.
+type LeastConnections struct{}
+
+func (LeastConnections) Pick(_ context.Context, replicas []Replica) Connection {
+	best, bestInUse := replicas[0], replicas[0].Conn.Stats().InUse
+	for _, r := range replicas[1:] {
+		if inUse := r.Conn.Stats().InUse; inUse < bestInUse {
+			best, bestInUse = r, inUse
+		}
+	}
+	return best.Conn
+}
+
+// WeightedRoundRobin spreads reads in proportion to replica weights, smoothly
+// interleaving them: weights 2 and 1 yield A, B, A, A, B, A...
+type WeightedRoundRobin struct {
+	mu      sync.Mutex
+	current map[string]int // Smooth weighted round robin state, by replica name
+}
+
+func (b *WeightedRoundRobin) Pick(_ context.Context, replicas []Replica) Connection {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.current == nil {
+		b.current = make(map[string]int, len(replicas))
+	}
+
+	total, best := 0, -1
+	for i, r := range replicas {
+		total += r.Weight
+		b.current[r.Name] += r.Weight
+		if best < 0 || b.current[r.Name] > b.current[replicas[best].Name] {
+			best = i
+		}
+	}
+	b.current[replicas[best].Name] -= total
+	return replicas[best].Conn
+}
@@ -0,0 +1,97 @@
+package connector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubConn is a Connection reporting inUse connections in use; other methods
+// are unused.
+type stubConn struct {
+	Connection
+	name  string
+	inUse int
+}
+
+func (c *stubConn) Stats() ConnectionStats { return ConnectionStats{InUse: c.inUse} }
+
+func replicaSet(weights ...int) []Replica {
+	replicas := make([]Replica, len(weights))
+	for i, w := range weights {
+		name := string(rune('A' + i))
+		replicas[i] = Replica{Name: name, Conn: &stubConn{name: name}, Weight: w}
+	}
+	return replicas
+}
+
+func picks(b ReadBalancer, replicas []Replica, n int) []string {
+	names := make([]string, n)
+	for i := range names {
+		names[i] = b.Pick(context.Background(), replicas).(*stubConn).name
+	}
+	return names
+}
+
+func TestNewReadBalancer(t *testing.T) {
+	for strategy, want := range map[string]ReadBalancer{
+		"":                     nil,
+		"primary":              nil,
+		"round_robin":          &RoundRobin{},
+		"random":               Random{},
+		"closest":              Closest{},
+		"least_connections":    LeastConnections{},
+		"weighted_round_robin": &WeightedRoundRobin{},
+	} {
+		b, err := NewReadBalancer(strategy)
+		require.NoError(t, err, strategy)
+		assert.IsType(t, want, b, strategy)
+	}
+
+	_, err := NewReadBalancer("fastest")
+	assert.EqualError(t, err, "invalid read strategy: fastest")
+}
+
+func TestRoundRobin(t *testing.T) {
+	assert.Equal(t, []string{"A", "B", "C", "A", "B"}, picks(&RoundRobin{}, replicaSet(1, 1, 1), 5))
+}
+
+func TestWeightedRoundRobin(t *testing.T) {
+	b := &WeightedRoundRobin{}
+	assert.Equal(t, []string{"A", "B", "A", "A", "B", "A"}, picks(b, replicaSet(2, 1), 6))
+
+	// State is kept by name, so an ejected replica does not shift the others
+	assert.Equal(t, []string{"B", "B"}, picks(b, replicaSet(2, 1)[1:], 2))
+}
+
+func TestClosestPrefersMeasuredReplicas(t *testing.T) {
+	replicas := replicaSet(1, 1, 1)
+	assert.Equal(t, "A", picks(Closest{}, replicas, 1)[0], "none measured")
+
+	replicas[1].Latency = 5 * time.Millisecond
+	replicas[2].Latency = 2 * time.Millisecond
+	assert.Equal(t, "C", picks(Closest{}, replicas, 1)[0])
+
+	replicas[2].Latency = 0
+	assert.Equal(t, "B", picks(Closest{}, replicas, 1)[0])
+}
+
+func TestLeastConnections(t *testing.T) {
+	replicas := replicaSet(1, 1, 1)
+	replicas[0].Conn.(*stubConn).inUse = 4
+	replicas[1].Conn.(*stubConn).inUse = 1
+	replicas[2].Conn.(*stubConn).inUse = 3
+	assert.Equal(t, "B", picks(LeastConnections{}, replicas, 1)[0])
+}
+
+type pickNone struct{}
+
+func (pickNone) Pick(context.Context, []Replica) Connection { return nil }
+
+func TestReadFallsBackToPrimaryWhenBalancerPicksNone(t *testing.T) {
+	pc := newTestCluster(pickNone{}, Config{Host: "r0"})
+	assert.Same(t, pc.primary, pc.Read(context.Background()))
+}
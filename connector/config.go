@@ -3,9 +3,12 @@ package connector
 import (
 	"fmt"
 	"time"
+
+	"github.com/Konsultn-Engineering/enorm/trace"
 )
 
-// Config represents database connection configuration.
+// Config represents database connection configuration. Printing or
+// JSON-encoding a Config redacts the password.
 type Config struct {
 	Host           string            `json:"host" yaml:"host"`
 	Port           int               `json:"port" yaml:"port"`
@@ -18,6 +21,17 @@ type Config struct {
 	ConnectTimeout time.Duration     `json:"connect_timeout" yaml:"connect_timeout"`
 	QueryTimeout   time.Duration     `json:"query_timeout" yaml:"query_timeout"`
 	Retry          *RetryConfig      `json:"retry,omitempty" yaml:"retry,omitempty"`
+	Weight         int               `json:"weight,omitempty" yaml:"weight,omitempty"` // Share of reads under weighted_round_robin, default 1
+
+	// PasswordProvider, when set, supplies the password of every new
+	// connection instead of Password. Supported by the postgres driver and
+	// by drivers registered through SQLDriver; custom Factory
+	// implementations must honour it themselves.
+	PasswordProvider PasswordProvider `json:"-" yaml:"-"`
+
+	// Tracer, when set, receives an "ACQUIRE" span each time a connection is
+	// taken from the pool.
+	Tracer trace.Tracer `json:"-" yaml:"-"`
 }
 
 // PoolConfig defines connection pool settings.
@@ -26,7 +40,7 @@ type PoolConfig struct {
 	MaxIdle         int           `json:"max_idle" yaml:"max_idle"`
 	MaxLifetime     time.Duration `json:"max_lifetime" yaml:"max_lifetime"`
 	MaxIdleTime     time.Duration `json:"max_idle_time" yaml:"max_idle_time"`
-	HealthCheckFreq time.Duration `json:"health_check_freq" yaml:"health_check_freq"`
+	HealthCheckFreq time.Duration `json:"health_check_freq" yaml:"health_check_freq"` // Cluster member ping interval, set on the primary; 0 disables
 }
 
 // RetryConfig defines connection retry behavior.
@@ -43,7 +57,10 @@ type ClusterConfig struct {
 	Replicas      []Config      `json:"replicas" yaml:"replicas"`
 	ReadStrategy  string        `json:"read_strategy" yaml:"read_strategy"`
 	WriteStrategy string        `json:"write_strategy" yaml:"write_strategy"`
-	FailoverDelay time.Duration `json:"failover_delay" yaml:"failover_delay"`
+	FailoverDelay time.Duration `json:"failover_delay" yaml:"failover_delay"` // How long an ejected replica must stay healthy to rejoin reads
+
+	// Balancer, when set, replaces the ReadStrategy
+	Balancer ReadBalancer `json:"-" yaml:"-"`
 }
 
 // ValidateCluster validates cluster configuration.
@@ -52,15 +69,15 @@ func (cc *ClusterConfig) ValidateCluster() error {
 		return fmt.Errorf("primary host is required")
 	}
 
-	validStrategies := map[string]bool{
-		"round_robin": true,
-		"random":      true,
-		"primary":     true,
-		"closest":     true,
+	if cc.Balancer == nil {
+		if _, err := NewReadBalancer(cc.ReadStrategy); err != nil {
+			return err
+		}
 	}
-
-	if cc.ReadStrategy != "" && !validStrategies[cc.ReadStrategy] {
-		return fmt.Errorf("invalid read strategy: %s", cc.ReadStrategy)
+	for i, r := range cc.Replicas {
+		if r.Weight < 0 {
+			return fmt.Errorf("replica %d: weight must not be negative", i)
+		}
 	}
 
 	if cc.WriteStrategy != "" && cc.WriteStrategy != "primary" {
@@ -4,6 +4,10 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
 	"github.com/Konsultn-Engineering/enorm/database"
 	"github.com/Konsultn-Engineering/enorm/dialect"
 )
@@ -27,14 +31,48 @@ type ClusterConnection interface {
 	Write(ctx context.Context) Connection
 }
 
-// New creates a new database connection for the specified driver.
+// Factory opens a connection from a Config, for Register.
+type Factory func(cfg Config) (Connection, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = map[string]Factory{
+		"postgres": func(cfg Config) (Connection, error) { return newPostgresConnector(cfg) },
+	}
+)
+
+// Register makes a driver available to New under name, typically from an
+// init function next to the import of its database/sql driver (see
+// SQLDriver). Registering a name twice, or a nil factory, panics.
+func Register(name string, factory Factory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+	if factory == nil {
+		panic("connector: Register factory is nil")
+	}
+	if _, dup := factories[name]; dup {
+		panic("connector: Register called twice for driver " + name)
+	}
+	factories[name] = factory
+}
+
+// Drivers returns the sorted names of the registered drivers.
+func Drivers() []string {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+	return slices.Sorted(maps.Keys(factories))
+}
+
+// New creates a new database connection for the specified driver: "postgres"
+// or one added with Register.
 func New(driver string, cfg Config) (Connection, error) {
-	switch driver {
-	case "postgres":
-		return newPostgresConnector(cfg)
-	default:
+	factoriesMu.RLock()
+	factory, ok := factories[driver]
+	factoriesMu.RUnlock()
+	if !ok {
 		return nil, fmt.Errorf("unsupported driver: %s", driver)
 	}
+	return factory(cfg)
 }
 
 // NewCluster creates a new cluster connection for the specified driver.
@@ -0,0 +1,59 @@
+package connector
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// register registers factory under name for the duration of the test.
+func register(t *testing.T, name string, factory Factory) {
+	t.Helper()
+	Register(name, factory)
+	t.Cleanup(func() {
+		factoriesMu.Lock()
+		delete(factories, name)
+		factoriesMu.Unlock()
+	})
+}
+
+func TestRegisterAndNew(t *testing.T) {
+	register(t, "stub", stubFactory)
+
+	conn, err := New("stub", Config{Host: "ok"})
+	require.NoError(t, err)
+	defer conn.Close()
+	assert.IsType(t, &SQLConnector{}, conn)
+
+	_, err = New("stub", Config{Host: "down"})
+	assert.EqualError(t, err, "failed to connect to enormstub: connection refused")
+
+	_, err = New("oracle", Config{})
+	assert.EqualError(t, err, "unsupported driver: oracle")
+}
+
+func TestRegisterPanics(t *testing.T) {
+	register(t, "stub", stubFactory)
+
+	assert.PanicsWithValue(t, "connector: Register called twice for driver stub", func() {
+		Register("stub", stubFactory)
+	})
+	assert.PanicsWithValue(t, "connector: Register called twice for driver postgres", func() {
+		Register("postgres", stubFactory)
+	})
+	assert.PanicsWithValue(t, "connector: Register factory is nil", func() {
+		Register("other", nil)
+	})
+	assert.NotContains(t, Drivers(), "other")
+}
+
+func TestDrivers(t *testing.T) {
+	register(t, "zz_stub", stubFactory)
+	register(t, "aa_stub", stubFactory)
+
+	drivers := Drivers()
+	assert.True(t, slices.IsSorted(drivers))
+	assert.Subset(t, drivers, []string{"aa_stub", "postgres", "zz_stub"})
+}
@@ -1,9 +1,12 @@
 package connector
 
 import (
+	"errors"
+	"fmt"
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // DSNBuilder provides a fluent interface for building database connection strings.
@@ -73,11 +76,11 @@ func (b *DSNBuilder) Build() string {
 
 	// Authentication
 	if b.username != "" {
-		dsn.WriteString(url.QueryEscape(b.username))
+		user := url.User(b.username)
 		if b.password != "" {
-			dsn.WriteString(":")
-			dsn.WriteString(url.QueryEscape(b.password))
+			user = url.UserPassword(b.username, b.password)
 		}
+		dsn.WriteString(user.String())
 		dsn.WriteString("@")
 	}
 
@@ -111,3 +114,61 @@ func (b *DSNBuilder) Build() string {
 
 	return dsn.String()
 }
+
+// ParseDSN parses a connection URL such as
+// "postgres://user:secret@db:5432/app?sslmode=require&connect_timeout=5" into
+// a Config, as the inverse of DSNBuilder.Build. sslmode and connect_timeout
+// (in seconds) fill SSLMode and ConnectTimeout; other parameters go to
+// Params.
+func ParseDSN(dsn string) (Config, error) {
+	var cfg Config
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return cfg, fmt.Errorf("invalid DSN: %w", redactURLError(err))
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return cfg, fmt.Errorf("invalid DSN: expected scheme://[user[:password]@]host[:port][/database]")
+	}
+
+	if u.User != nil {
+		cfg.Username = u.User.Username()
+		cfg.Password, _ = u.User.Password()
+	}
+	cfg.Host = u.Hostname()
+	if port := u.Port(); port != "" {
+		if cfg.Port, err = strconv.Atoi(port); err != nil {
+			return cfg, fmt.Errorf("invalid DSN port %q", port)
+		}
+	}
+	cfg.Database = strings.TrimPrefix(u.Path, "/")
+
+	for key, values := range u.Query() {
+		value := values[len(values)-1]
+		switch key {
+		case "sslmode":
+			cfg.SSLMode = value
+		case "connect_timeout":
+			seconds, err := strconv.Atoi(value)
+			if err != nil {
+				return cfg, fmt.Errorf("invalid DSN connect_timeout %q", value)
+			}
+			cfg.ConnectTimeout = time.Duration(seconds) * time.Second
+		default:
+			if cfg.Params == nil {
+				cfg.Params = make(map[string]string)
+			}
+			cfg.Params[key] = value
+		}
+	}
+	return cfg, nil
+}
+
+// redactURLError drops the URL from a url.Parse error, as it may contain a
+// password.
+func redactURLError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
@@ -0,0 +1,60 @@
+package connector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDSNBuilderEscapesUserinfo(t *testing.T) {
+	dsn := NewDSNBuilder("postgres").
+		Auth("app user", "p@ss:w/rd?#%").
+		Host("db", 5432).
+		Database("my db").
+		Build()
+	assert.Equal(t, "postgres://app%20user:p%40ss%3Aw%2Frd%3F%23%25@db:5432/my%20db", dsn)
+
+	assert.Equal(t, "postgres://app@db", NewDSNBuilder("postgres").Auth("app", "").Host("db", 0).Build())
+}
+
+func TestParseDSNRoundTrip(t *testing.T) {
+	for _, password := range []string{"secret", "p@ss:w/rd?#%", "a b+c&d=e", `quo"te'\`, "ünïcødé"} {
+		dsn := NewDSNBuilder("postgres").
+			Auth("app", password).
+			Host("db.internal", 6432).
+			Database("app").
+			Param("sslmode", "require").
+			Param("application_name", "api").
+			Build()
+
+		cfg, err := ParseDSN(dsn)
+		require.NoError(t, err, dsn)
+		assert.Equal(t, Config{
+			Host:     "db.internal",
+			Port:     6432,
+			Database: "app",
+			Username: "app",
+			Password: password,
+			SSLMode:  "require",
+			Params:   map[string]string{"application_name": "api"},
+		}, cfg, dsn)
+	}
+}
+
+func TestParseDSN(t *testing.T) {
+	cfg, err := ParseDSN("postgres://db?connect_timeout=5")
+	require.NoError(t, err)
+	assert.Equal(t, Config{Host: "db", ConnectTimeout: 5 * time.Second}, cfg)
+
+	for dsn, want := range map[string]string{
+		"db:5432":                         "invalid DSN: expected scheme://[user[:password]@]host[:port][/database]",
+		"postgres://db?connect_timeout=x": `invalid DSN connect_timeout "x"`,
+		"postgres://db:port":              "invalid DSN: invalid port \":port\" after host",
+		"postgres://app:s3cret@db:port/":  "invalid DSN: invalid port \":port\" after host",
+	} {
+		_, err := ParseDSN(dsn)
+		assert.EqualError(t, err, want, dsn)
+	}
+}
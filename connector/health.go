@@ -0,0 +1,224 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// MemberState is the health of one cluster member as last seen by the health
+// monitor (see PoolConfig.HealthCheckFreq).
+type MemberState struct {
+	Name      string // "primary" or "replica_N"
+	Host      string
+	Healthy   bool          // False while a replica is out of the read rotation
+	LastCheck time.Time     // Zero until the first check
+	LastError string        // Error of the last failed check, "" once healthy again
+	Latency   time.Duration // Rolling ping latency
+	Lag       time.Duration // Replication lag of a replica at LastCheck
+	LagError  string        // Error of the last failed lag measurement
+}
+
+// member tracks the health of a cluster member.
+type member struct {
+	name string
+	conn *PostgresConnector
+
+	mu        sync.RWMutex
+	healthy   bool
+	upSince   time.Time // Start of the current run of passed checks, zero after a failure
+	lastCheck time.Time
+	lastErr   error
+	latency   time.Duration // Exponentially weighted moving average of ping times
+	lag       time.Duration
+	lagKnown  bool // Set while the last lag measurement succeeded
+	lagErr    error
+}
+
+func newMember(name string, conn *PostgresConnector) *member {
+	return &member{name: name, conn: conn, healthy: true}
+}
+
+func replicaName(i int) string {
+	return "replica_" + strconv.Itoa(i)
+}
+
+// latencyWeight is the weight of the newest ping in the rolling latency.
+const latencyWeight = 0.3
+
+// recordLag stores the outcome of measuring a replica's replication lag. A
+// failed measurement leaves the replica in the read rotation, since it still
+// answers pings, but makes its lag unknown until the next success.
+func (m *member) recordLag(lag time.Duration, err error) {
+	m.mu.Lock()
+	m.lag, m.lagKnown, m.lagErr = lag, err == nil, err
+	m.mu.Unlock()
+}
+
+// record updates the member with the outcome of a check made at now that took
+// took. A failure ejects the member at once; it is reinstated after passing
+// every check for reinstateAfter.
+func (m *member) record(now time.Time, took time.Duration, err error, reinstateAfter time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lastCheck = now
+	m.lastErr = err
+	if err != nil {
+		m.healthy = false
+		m.upSince = time.Time{}
+		return
+	}
+
+	if m.latency == 0 {
+		m.latency = took
+	} else {
+		m.latency = time.Duration(latencyWeight*float64(took) + (1-latencyWeight)*float64(m.latency))
+	}
+	if m.upSince.IsZero() {
+		m.upSince = now
+	}
+	if !m.healthy && now.Sub(m.upSince) >= reinstateAfter {
+		m.healthy = true
+	}
+}
+
+func (m *member) state() MemberState {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	s := MemberState{
+		Name:      m.name,
+		Host:      m.conn.config.Host,
+		Healthy:   m.healthy,
+		LastCheck: m.lastCheck,
+		Latency:   m.latency,
+		Lag:       m.lag,
+	}
+	if m.lastErr != nil {
+		s.LastError = m.lastErr.Error()
+	}
+	if m.lagErr != nil {
+		s.LagError = m.lagErr.Error()
+	}
+	return s
+}
+
+// startMonitor pings every member each freq until Close.
+func (pc *PostgresCluster) startMonitor(freq time.Duration) {
+	pc.stop = make(chan struct{})
+	pc.done = make(chan struct{})
+
+	go func() {
+		defer close(pc.done)
+		ticker := time.NewTicker(freq)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-pc.stop:
+				return
+			case <-ticker.C:
+				pc.checkMembers(freq)
+			}
+		}
+	}()
+}
+
+// checkMembers pings all members concurrently, each bounded by timeout.
+func (pc *PostgresCluster) checkMembers(timeout time.Duration) {
+	var wg sync.WaitGroup
+	for _, m := range pc.allMembers() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+			start := time.Now()
+			err := m.conn.Health(ctx)
+			m.record(start, time.Since(start), err, pc.config.FailoverDelay)
+			if err == nil && m != pc.primaryState {
+				m.recordLag(m.conn.replicationLag(ctx))
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// stopMonitor stops the health monitor, if running, and waits for it to exit.
+func (pc *PostgresCluster) stopMonitor() {
+	if pc.stop != nil {
+		close(pc.stop)
+		<-pc.done
+		pc.stop = nil
+	}
+}
+
+func (pc *PostgresCluster) allMembers() []*member {
+	return append([]*member{pc.primaryState}, pc.replicaStates...)
+}
+
+// healthyReplicas returns the replicas in the read rotation, leaving out
+// those behind the primary by more than the MaxStaleness of ctx.
+func (pc *PostgresCluster) healthyReplicas(ctx context.Context) []Replica {
+	maxStaleness, bounded := MaxStalenessFromContext(ctx)
+	healthy := make([]Replica, 0, len(pc.replicaStates))
+	for _, m := range pc.replicaStates {
+		m.mu.RLock()
+		fresh := !bounded || (m.lagKnown && m.lag <= maxStaleness)
+		if m.healthy && fresh {
+			healthy = append(healthy, Replica{
+				Name:    m.name,
+				Conn:    m.conn,
+				Weight:  max(m.conn.config.Weight, 1),
+				Latency: m.latency,
+				Lag:     m.lag,
+			})
+		}
+		m.mu.RUnlock()
+	}
+	return healthy
+}
+
+// replicationLagQuery returns how far a standby is behind, in seconds: 0 when
+// it has replayed everything it received (an idle primary leaves the last
+// replay timestamp old), and NULL on a server that is not a standby.
+const replicationLagQuery = `SELECT CASE
+	WHEN NOT pg_is_in_recovery() THEN NULL
+	WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+	ELSE EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp())
+END`
+
+// replicationLag measures the replication lag of a standby from
+// pg_last_xact_replay_timestamp().
+func (p *PostgresConnector) replicationLag(ctx context.Context) (time.Duration, error) {
+	pool := p.currentPool()
+	if pool == nil {
+		return 0, fmt.Errorf("not connected")
+	}
+	var seconds *float64
+	if err := pool.QueryRow(ctx, replicationLagQuery).Scan(&seconds); err != nil {
+		return 0, fmt.Errorf("measure replication lag: %w", err)
+	}
+	if seconds == nil {
+		return 0, nil
+	}
+	return time.Duration(*seconds * float64(time.Second)), nil
+}
+
+type maxStalenessKey struct{}
+
+// WithMaxStaleness returns a copy of ctx under which cluster reads skip
+// replicas whose replication lag, as measured by the health monitor, exceeds
+// d. Replicas are skipped too while their lag is unknown: until it has been
+// measured, and after a failed measurement. Without any fresh replica, reads
+// go to the primary.
+func WithMaxStaleness(ctx context.Context, d time.Duration) context.Context {
+	return context.WithValue(ctx, maxStalenessKey{}, d)
+}
+
+// MaxStalenessFromContext returns the bound set by WithMaxStaleness.
+func MaxStalenessFromContext(ctx context.Context) (time.Duration, bool) {
+	d, ok := ctx.Value(maxStalenessKey{}).(time.Duration)
+	return d, ok
+}
@@ -0,0 +1,164 @@
+package connector
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestCluster returns a cluster of unconnected members: health checks fail
+// with "not connected" unless recorded by hand.
+func newTestCluster(balancer ReadBalancer, replicas ...Config) *PostgresCluster {
+	primary := &PostgresConnector{config: Config{Host: "primary"}}
+	pc := &PostgresCluster{
+		config:       ClusterConfig{FailoverDelay: 30 * time.Second},
+		primary:      primary,
+		balancer:     balancer,
+		primaryState: newMember("primary", primary),
+	}
+	for i, cfg := range replicas {
+		replica := &PostgresConnector{config: cfg}
+		pc.replicas = append(pc.replicas, replica)
+		pc.replicaStates = append(pc.replicaStates, newMember(replicaName(i), replica))
+	}
+	return pc
+}
+
+func TestMemberEjectionAndReinstatement(t *testing.T) {
+	const delay = 30 * time.Second
+	m := newMember("replica_0", &PostgresConnector{config: Config{Host: "r0"}})
+	t0 := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	down := errors.New("connection refused")
+
+	m.record(t0, time.Millisecond, nil, delay)
+	assert.True(t, m.state().Healthy)
+
+	// A single failure ejects the member at once
+	m.record(t0.Add(time.Second), time.Millisecond, down, delay)
+	s := m.state()
+	assert.False(t, s.Healthy)
+	assert.Equal(t, "connection refused", s.LastError)
+	assert.Equal(t, t0.Add(time.Second), s.LastCheck)
+	assert.Equal(t, "r0", s.Host)
+
+	m.record(t0.Add(2*time.Second), time.Millisecond, nil, delay)
+	assert.False(t, m.state().Healthy)
+	assert.Empty(t, m.state().LastError)
+	m.record(t0.Add(20*time.Second), time.Millisecond, nil, delay)
+	assert.False(t, m.state().Healthy)
+
+	// Another failure restarts the delay
+	m.record(t0.Add(25*time.Second), time.Millisecond, down, delay)
+	m.record(t0.Add(26*time.Second), time.Millisecond, nil, delay)
+	m.record(t0.Add(55*time.Second), time.Millisecond, nil, delay)
+	assert.False(t, m.state().Healthy)
+
+	m.record(t0.Add(56*time.Second), time.Millisecond, nil, delay)
+	assert.True(t, m.state().Healthy)
+}
+
+func TestMemberLatencyAverage(t *testing.T) {
+	m := newMember("replica_0", &PostgresConnector{})
+	now := time.Now()
+
+	m.record(now, 100*time.Millisecond, nil, 0)
+	assert.Equal(t, 100*time.Millisecond, m.state().Latency)
+
+	m.record(now, 200*time.Millisecond, nil, 0)
+	assert.Equal(t, 130*time.Millisecond, m.state().Latency)
+
+	// Failed checks do not move the average
+	m.record(now, time.Second, errors.New("timeout"), 0)
+	assert.Equal(t, 130*time.Millisecond, m.state().Latency)
+}
+
+func TestReadFallsBackToPrimaryWhenReplicasAreEjected(t *testing.T) {
+	pc := newTestCluster(&RoundRobin{}, Config{Host: "r0"}, Config{Host: "r1"})
+	ctx := context.Background()
+
+	assert.Same(t, pc.replicas[0], pc.Read(ctx))
+	assert.Same(t, pc.replicas[1], pc.Read(ctx))
+
+	pc.replicaStates[0].record(time.Now(), 0, errors.New("down"), time.Minute)
+	assert.Same(t, pc.replicas[1], pc.Read(ctx))
+	assert.Same(t, pc.replicas[1], pc.Read(ctx))
+
+	pc.replicaStates[1].record(time.Now(), 0, errors.New("down"), time.Minute)
+	assert.Same(t, pc.primary, pc.Read(ctx))
+	assert.Same(t, pc.primary, pc.Write(ctx))
+}
+
+func TestMonitorChecksMembersUntilStopped(t *testing.T) {
+	pc := newTestCluster(&RoundRobin{}, Config{Host: "r0"})
+	pc.startMonitor(time.Millisecond)
+
+	// Unconnected members fail their checks and the replica is ejected
+	require.Eventually(t, func() bool {
+		return !pc.replicaStates[0].state().LastCheck.IsZero()
+	}, time.Second, time.Millisecond)
+
+	pc.stopMonitor()
+	assert.Nil(t, pc.stop)
+	select {
+	case <-pc.done:
+	default:
+		t.Fatal("monitor goroutine still running after stopMonitor")
+	}
+
+	s := pc.replicaStates[0].state()
+	assert.False(t, s.Healthy)
+	assert.Equal(t, "not connected", s.LastError)
+	assert.Same(t, pc.primary, pc.Read(context.Background()))
+
+	// No more checks once stopped, and stopping again is a no-op
+	time.Sleep(5 * time.Millisecond)
+	assert.Equal(t, s.LastCheck, pc.replicaStates[0].state().LastCheck)
+	pc.stopMonitor()
+}
+
+func TestHealthyReplicasWithMaxStaleness(t *testing.T) {
+	pc := newTestCluster(&RoundRobin{}, Config{Weight: 3}, Config{}, Config{}, Config{})
+	now := time.Now()
+	for _, m := range pc.replicaStates {
+		m.record(now, 2*time.Millisecond, nil, 0)
+	}
+	pc.replicaStates[0].recordLag(time.Second, nil)
+	pc.replicaStates[1].recordLag(10*time.Second, nil)
+	// replica_2 has not been measured yet
+	pc.replicaStates[3].recordLag(time.Second, nil)
+	pc.replicaStates[3].recordLag(0, errors.New("measure replication lag: permission denied"))
+
+	names := func(replicas []Replica) []string {
+		var out []string
+		for _, r := range replicas {
+			out = append(out, r.Name)
+		}
+		return out
+	}
+
+	// Without a bound every healthy replica serves reads, whatever its lag
+	all := pc.healthyReplicas(context.Background())
+	assert.Equal(t, []string{"replica_0", "replica_1", "replica_2", "replica_3"}, names(all))
+	assert.Equal(t, Replica{Name: "replica_0", Conn: pc.replicas[0], Weight: 3, Latency: 2 * time.Millisecond, Lag: time.Second}, all[0])
+	assert.Equal(t, 1, all[1].Weight)
+
+	ctx := WithMaxStaleness(context.Background(), 5*time.Second)
+	d, ok := MaxStalenessFromContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, 5*time.Second, d)
+	assert.Equal(t, []string{"replica_0"}, names(pc.healthyReplicas(ctx)))
+
+	// A failed lag measurement does not eject the replica
+	s := pc.replicaStates[3].state()
+	assert.True(t, s.Healthy)
+	assert.Empty(t, s.LastError)
+	assert.Equal(t, "measure replication lag: permission denied", s.LagError)
+
+	ctx = WithMaxStaleness(context.Background(), 100*time.Millisecond)
+	assert.Empty(t, pc.healthyReplicas(ctx))
+	assert.Same(t, pc.primary, pc.Read(ctx))
+}
@@ -0,0 +1,283 @@
+package connector
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix prefixes the environment variables read by LoadConfig.
+const EnvPrefix = "ENORM"
+
+// LoadConfig reads a Config from a JSON or YAML file and applies environment
+// overrides, so one binary can be configured per environment:
+//
+//   - ${VAR} in a value is replaced by the variable's value, or by default
+//     for ${VAR:-default}; an unset variable without default is an error.
+//     References are expanded after parsing, so values cannot change the
+//     structure of the file. Quoted values stay strings; unquoted ones are
+//     typed by what they expand to, e.g. port: ${DB_PORT}.
+//   - ENORM_DSN, when set, overrides the connection fields it names (see
+//     ParseDSN).
+//   - ENORM_<FIELD> overrides a field, named after its yaml tag:
+//     ENORM_HOST, ENORM_PORT, ENORM_POOL_MAX_OPEN, ENORM_RETRY_MAX_RETRIES...
+//     Durations use time.ParseDuration syntax.
+//
+// With an empty path, the Config comes from the environment alone.
+//
+// Example config.yaml:
+//
+//	host: db.internal
+//	port: 5432
+//	database: app
+//	username: app
+//	password: "${DB_PASSWORD}"
+//	pool:
+//	  max_open: 20
+//	  max_lifetime: 1h
+func LoadConfig(path string) (Config, error) {
+	var cfg Config
+	if path != "" {
+		if err := decodeFile(path, &cfg); err != nil {
+			return cfg, err
+		}
+	}
+
+	if dsn, ok := os.LookupEnv(EnvPrefix + "_DSN"); ok {
+		parsed, err := ParseDSN(dsn)
+		if err != nil {
+			return cfg, fmt.Errorf("%s_DSN: %w", EnvPrefix, err)
+		}
+		cfg.mergeConnection(parsed)
+	}
+
+	if err := applyEnv(EnvPrefix, reflect.ValueOf(&cfg).Elem()); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+// LoadClusterConfig reads a ClusterConfig like LoadConfig. Environment
+// overrides apply to the primary (ENORM_PRIMARY_HOST...) and to cluster
+// settings (ENORM_READ_STRATEGY...), not to individual replicas.
+func LoadClusterConfig(path string) (ClusterConfig, error) {
+	var cfg ClusterConfig
+	if path != "" {
+		if err := decodeFile(path, &cfg); err != nil {
+			return cfg, err
+		}
+	}
+	if err := applyEnv(EnvPrefix, reflect.ValueOf(&cfg).Elem()); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+// mergeConnection overrides the connection fields set in o.
+func (c *Config) mergeConnection(o Config) {
+	if o.Host != "" {
+		c.Host = o.Host
+	}
+	if o.Port != 0 {
+		c.Port = o.Port
+	}
+	if o.Database != "" {
+		c.Database = o.Database
+	}
+	if o.Username != "" {
+		c.Username = o.Username
+		c.Password = o.Password
+	}
+	if o.SSLMode != "" {
+		c.SSLMode = o.SSLMode
+	}
+	if o.ConnectTimeout != 0 {
+		c.ConnectTimeout = o.ConnectTimeout
+	}
+	for k, v := range o.Params {
+		if c.Params == nil {
+			c.Params = make(map[string]string, len(o.Params))
+		}
+		c.Params[k] = v
+	}
+}
+
+// decodeFile decodes a config file and interpolates its values. JSON is
+// decoded as YAML, of which it is a subset, so durations may be written as
+// "30s" in both.
+func decodeFile(path string, out any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if doc.Kind == 0 {
+		return nil // Empty file
+	}
+	if err := interpolateEnv(&doc); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	// Decode from a re-encoded document: yaml.Node.Decode cannot reject
+	// unknown fields
+	if data, err = yaml.Marshal(&doc); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(out); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+var envRef = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:-[^}]*)?\}`)
+
+// quotedStyles keep a scalar a string whatever its value.
+const quotedStyles = yaml.TaggedStyle | yaml.DoubleQuotedStyle | yaml.SingleQuotedStyle | yaml.LiteralStyle | yaml.FoldedStyle
+
+// interpolateEnv replaces ${VAR} and ${VAR:-default} references in the
+// scalar values under n. Mapping keys and comments are left alone.
+func interpolateEnv(n *yaml.Node) error {
+	var missing []string
+	var walk func(n *yaml.Node)
+	walk = func(n *yaml.Node) {
+		switch n.Kind {
+		case yaml.DocumentNode, yaml.SequenceNode:
+			for _, child := range n.Content {
+				walk(child)
+			}
+		case yaml.MappingNode:
+			for i := 1; i < len(n.Content); i += 2 {
+				walk(n.Content[i])
+			}
+		case yaml.ScalarNode:
+			value := envRef.ReplaceAllStringFunc(n.Value, func(ref string) string {
+				m := envRef.FindStringSubmatch(ref)
+				if value, ok := os.LookupEnv(m[1]); ok {
+					return value
+				}
+				if m[2] != "" {
+					return m[2][2:] // Strip ":-"
+				}
+				missing = append(missing, m[1])
+				return ""
+			})
+			if value != n.Value {
+				n.Value = value
+				if n.Style&quotedStyles == 0 {
+					n.Tag = "" // Resolve the type from the new value
+				}
+			}
+		}
+	}
+	walk(n)
+
+	if len(missing) > 0 {
+		return fmt.Errorf("environment variables not set: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// applyEnv sets the fields of the struct v from PREFIX_<TAG> variables,
+// recursing into nested structs. Pointers to structs are allocated when a
+// variable names one of their fields.
+func applyEnv(prefix string, v reflect.Value) error {
+	t := v.Type()
+	for i := range t.NumField() {
+		tag, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		name := prefix + "_" + strings.ToUpper(tag)
+		field := v.Field(i)
+
+		switch {
+		case field.Kind() == reflect.Struct:
+			if err := applyEnv(name, field); err != nil {
+				return err
+			}
+		case field.Kind() == reflect.Pointer && field.Type().Elem().Kind() == reflect.Struct:
+			if !hasEnvPrefix(name + "_") {
+				continue
+			}
+			if field.IsNil() {
+				field.Set(reflect.New(field.Type().Elem()))
+			}
+			if err := applyEnv(name, field.Elem()); err != nil {
+				return err
+			}
+		default:
+			value, ok := os.LookupEnv(name)
+			if !ok {
+				continue
+			}
+			if err := setFromString(field, value); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func hasEnvPrefix(prefix string) bool {
+	for _, kv := range os.Environ() {
+		if strings.HasPrefix(kv, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// setFromString parses s into a string, number, bool or duration field.
+func setFromString(field reflect.Value, s string) error {
+	if field.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	default:
+		return fmt.Errorf("cannot be set from the environment")
+	}
+	return nil
+}
@@ -4,12 +4,12 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"math/rand"
 	"sync"
 	"time"
 
 	"github.com/Konsultn-Engineering/enorm/database"
 	"github.com/Konsultn-Engineering/enorm/dialect"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/jackc/pgx/v5/stdlib"
 )
@@ -17,8 +17,12 @@ import (
 // PostgresConnector represents a PostgreSQL database connection.
 type PostgresConnector struct {
 	config  Config
-	pool    *pgxpool.Pool
 	dialect dialect.Dialect
+
+	mu       sync.RWMutex // Guards the pool and its adapters against Close
+	pool     *pgxpool.Pool
+	database *database.PgxDatabase
+	sqlDB    *sql.DB // Created on the first DB call
 }
 
 // PostgresCluster represents a PostgreSQL cluster with primary and replica connections.
@@ -26,8 +30,12 @@ type PostgresCluster struct {
 	config   ClusterConfig
 	primary  *PostgresConnector
 	replicas []*PostgresConnector
-	mu       sync.RWMutex
-	readIdx  int
+	balancer ReadBalancer // nil: reads go to the primary
+
+	// Health monitoring (see PoolConfig.HealthCheckFreq)
+	primaryState  *member
+	replicaStates []*member
+	stop, done    chan struct{}
 }
 
 // newPostgresConnector creates a new PostgreSQL connector with auto-connection.
@@ -82,20 +90,52 @@ func newPostgresCluster(cfg ClusterConfig) (*PostgresCluster, error) {
 		replicas = append(replicas, replica)
 	}
 
-	return &PostgresCluster{
-		config:   cfg,
-		primary:  primary,
-		replicas: replicas,
-	}, nil
+	pc := &PostgresCluster{
+		config:       cfg,
+		primary:      primary,
+		replicas:     replicas,
+		balancer:     cfg.Balancer,
+		primaryState: newMember("primary", primary),
+	}
+	if pc.balancer == nil {
+		// Already validated by ValidateCluster
+		pc.balancer, _ = NewReadBalancer(cfg.ReadStrategy)
+	}
+	for i, replica := range replicas {
+		pc.replicaStates = append(pc.replicaStates, newMember(replicaName(i), replica))
+	}
+	if freq := cfg.Primary.Pool.HealthCheckFreq; freq > 0 {
+		pc.startMonitor(freq)
+	}
+	return pc, nil
 }
 
 // connect establishes the PostgreSQL connection.
 func (p *PostgresConnector) connect(ctx context.Context) error {
-	if p.pool != nil {
+	if p.currentPool() != nil {
 		return nil // Already connected
 	}
 
-	dsn := p.buildDSN()
+	poolCfg, err := p.poolConfig()
+	if err != nil {
+		return err
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	p.pool = pool
+	p.database = database.NewPgxDatabase(pool)
+	p.mu.Unlock()
+	return nil
+}
+
+// poolConfig builds the pgxpool configuration for p.config, applying pool
+// defaults.
+func (p *PostgresConnector) poolConfig() (*pgxpool.Config, error) {
 	cfg := p.config
 
 	// Apply defaults
@@ -112,29 +152,47 @@ func (p *PostgresConnector) connect(ctx context.Context) error {
 		cfg.Pool.MaxIdleTime = 30 * time.Minute
 	}
 
-	poolCfg, err := pgxpool.ParseConfig(dsn)
+	poolCfg, err := pgxpool.ParseConfig(p.buildDSN())
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	poolCfg.MaxConns = int32(cfg.Pool.MaxOpen)
 	poolCfg.MinConns = int32(cfg.Pool.MaxIdle)
 	poolCfg.MaxConnLifetime = cfg.Pool.MaxLifetime
 	poolCfg.MaxConnIdleTime = cfg.Pool.MaxIdleTime
-
-	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
-	if err != nil {
-		return err
+	if provider := cfg.PasswordProvider; provider != nil {
+		poolCfg.BeforeConnect = func(ctx context.Context, cc *pgx.ConnConfig) error {
+			password, err := provider(ctx)
+			if err != nil {
+				return fmt.Errorf("password provider: %w", err)
+			}
+			cc.Password = password
+			return nil
+		}
+	}
+	if cfg.Tracer != nil {
+		poolCfg.ConnConfig.Tracer = pgxTracer{tracer: cfg.Tracer}
 	}
+	return poolCfg, nil
+}
 
-	p.pool = pool
-	return nil
+// currentPool returns the pool, or nil once closed.
+func (p *PostgresConnector) currentPool() *pgxpool.Pool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.pool
 }
 
-// buildDSN creates a PostgreSQL connection string.
+// buildDSN creates a PostgreSQL connection string. It leaves out the password
+// when a PasswordProvider supplies it per connection.
 func (p *PostgresConnector) buildDSN() string {
+	password := p.config.Password
+	if p.config.PasswordProvider != nil {
+		password = ""
+	}
 	return NewDSNBuilder("postgres").
-		Auth(p.config.Username, p.config.Password).
+		Auth(p.config.Username, password).
 		Host(p.config.Host, p.config.Port).
 		Database(p.config.Database).
 		Param("sslmode", p.config.SSLMode).
@@ -142,14 +200,34 @@ func (p *PostgresConnector) buildDSN() string {
 		Build()
 }
 
-// DB returns the underlying *sql.DB instance.
+// DB returns a *sql.DB backed by the connection pool. It is created on the
+// first call and shared by later ones; Close closes it. Returns nil once the
+// connector is closed.
 func (p *PostgresConnector) DB() *sql.DB {
-	return stdlib.OpenDBFromPool(p.pool)
+	p.mu.RLock()
+	db := p.sqlDB
+	p.mu.RUnlock()
+	if db != nil {
+		return db
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.sqlDB == nil && p.pool != nil {
+		p.sqlDB = stdlib.OpenDBFromPool(p.pool)
+	}
+	return p.sqlDB
 }
 
-// Database returns a database abstraction interface.
+// Database returns a database abstraction interface. Returns nil once the
+// connector is closed.
 func (p *PostgresConnector) Database() database.Database {
-	return database.NewPgxDatabase(p.pool)
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.database == nil {
+		return nil
+	}
+	return p.database
 }
 
 // Dialect returns the PostgreSQL dialect.
@@ -159,32 +237,50 @@ func (p *PostgresConnector) Dialect() dialect.Dialect {
 
 // Health checks the connection health.
 func (p *PostgresConnector) Health(ctx context.Context) error {
-	if p.pool == nil {
+	pool := p.currentPool()
+	if pool == nil {
 		return fmt.Errorf("not connected")
 	}
-	return p.pool.Ping(ctx)
+	return pool.Ping(ctx)
 }
 
 // Stats returns connection pool statistics.
 func (p *PostgresConnector) Stats() ConnectionStats {
-	if p.pool == nil {
+	pool := p.currentPool()
+	if pool == nil {
 		return ConnectionStats{}
 	}
-	s := p.pool.Stat()
+	s := pool.Stat()
 	return ConnectionStats{
-		OpenConnections: int(s.TotalConns()),
-		InUse:           int(s.AcquiredConns()),
-		Idle:            int(s.IdleConns()),
+		OpenConnections:         int(s.TotalConns()),
+		InUse:                   int(s.AcquiredConns()),
+		Idle:                    int(s.IdleConns()),
+		MaxOpen:                 int(s.MaxConns()),
+		AcquireCount:            s.AcquireCount(),
+		AcquireDuration:         s.AcquireDuration(),
+		CanceledAcquireCount:    s.CanceledAcquireCount(),
+		EmptyAcquireCount:       s.EmptyAcquireCount(),
+		NewConnections:          s.NewConnsCount(),
+		MaxLifetimeDestroyCount: s.MaxLifetimeDestroyCount(),
+		MaxIdleDestroyCount:     s.MaxIdleDestroyCount(),
 	}
 }
 
-// Close closes the connection pool.
+// Close closes the *sql.DB returned by DB, if any, and the connection pool.
 func (p *PostgresConnector) Close() error {
-	if p.pool != nil {
-		p.pool.Close()
-		p.pool = nil
+	p.mu.Lock()
+	pool, sqlDB := p.pool, p.sqlDB
+	p.pool, p.database, p.sqlDB = nil, nil, nil
+	p.mu.Unlock()
+
+	var err error
+	if sqlDB != nil {
+		err = sqlDB.Close()
 	}
-	return nil
+	if pool != nil {
+		pool.Close()
+	}
+	return err
 }
 
 // Cluster-specific methods
@@ -203,26 +299,22 @@ func (pc *PostgresCluster) Replicas() []Connection {
 	return connections
 }
 
-// Read returns a connection for read operations based on the configured strategy.
+// Read returns a connection for read operations, chosen by the configured
+// ReadBalancer among the replicas the health monitor considers healthy and,
+// when ctx carries a MaxStaleness, recent enough. Falls back to the primary
+// when none are, or when the balancer picks none.
 func (pc *PostgresCluster) Read(ctx context.Context) Connection {
-	if len(pc.replicas) == 0 {
+	if pc.balancer == nil {
 		return pc.primary
 	}
-
-	switch pc.config.ReadStrategy {
-	case "primary", "":
-		return pc.primary
-	case "random":
-		return pc.replicas[rand.Intn(len(pc.replicas))]
-	case "round_robin":
-		pc.mu.Lock()
-		idx := pc.readIdx % len(pc.replicas)
-		pc.readIdx++
-		pc.mu.Unlock()
-		return pc.replicas[idx]
-	default:
+	replicas := pc.healthyReplicas(ctx)
+	if len(replicas) == 0 {
 		return pc.primary
 	}
+	if conn := pc.balancer.Pick(ctx, replicas); conn != nil {
+		return conn
+	}
+	return pc.primary
 }
 
 // Write returns a connection for write operations (always primary).
@@ -260,22 +352,25 @@ func (pc *PostgresCluster) Health(ctx context.Context) error {
 	return nil
 }
 
-// Stats returns aggregated statistics from all connections.
+// Stats returns aggregated statistics from all connections, with the health
+// of each member in Members.
 func (pc *PostgresCluster) Stats() ConnectionStats {
 	primaryStats := pc.primary.Stats()
 
 	for _, replica := range pc.replicas {
-		replicaStats := replica.Stats()
-		primaryStats.OpenConnections += replicaStats.OpenConnections
-		primaryStats.InUse += replicaStats.InUse
-		primaryStats.Idle += replicaStats.Idle
+		primaryStats.add(replica.Stats())
 	}
 
+	for _, m := range pc.allMembers() {
+		primaryStats.Members = append(primaryStats.Members, m.state())
+	}
 	return primaryStats
 }
 
 // Close closes all connections in the cluster.
 func (pc *PostgresCluster) Close() error {
+	pc.stopMonitor()
+
 	var lastErr error
 
 	if err := pc.primary.Close(); err != nil {
@@ -0,0 +1,68 @@
+package connector
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newUnreachableConnector returns a connector whose pool points at a closed
+// port. The pool connects lazily, so it opens fine; pings fail.
+func newUnreachableConnector(t *testing.T) *PostgresConnector {
+	t.Helper()
+	p, err := newPostgresConnector(Config{Host: "127.0.0.1", Port: 1, Database: "app", Username: "app", SSLMode: "disable"})
+	require.NoError(t, err)
+	return p
+}
+
+func TestPostgresConnectorDBIsCachedUntilClose(t *testing.T) {
+	p := newUnreachableConnector(t)
+
+	db := p.DB()
+	require.NotNil(t, db)
+	assert.Same(t, db, p.DB())
+	assert.NotNil(t, p.Database())
+
+	require.NoError(t, p.Close())
+	assert.Nil(t, p.DB())
+	assert.Nil(t, p.Database())
+	assert.EqualError(t, p.Health(context.Background()), "not connected")
+	assert.Equal(t, ConnectionStats{}, p.Stats())
+
+	// The *sql.DB handed out before Close is closed with the pool
+	assert.ErrorContains(t, db.Ping(), "sql: database is closed")
+	assert.NoError(t, p.Close())
+}
+
+func TestPostgresConnectorConcurrentClose(t *testing.T) {
+	p := newUnreachableConnector(t)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 20 {
+				_ = p.Health(ctx)
+				_ = p.Stats()
+				_ = p.DB()
+				_ = p.Database()
+			}
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		time.Sleep(time.Millisecond)
+		assert.NoError(t, p.Close())
+	}()
+	wg.Wait()
+
+	assert.Nil(t, p.DB())
+}
@@ -0,0 +1,56 @@
+package connector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// redacted replaces secrets in printed and serialized configs.
+const redacted = "[REDACTED]"
+
+// PasswordProvider returns the password for a new connection. It is called
+// each time the pool opens a physical connection, so short-lived credentials
+// such as IAM auth tokens can be rotated without restarting.
+type PasswordProvider func(ctx context.Context) (string, error)
+
+// PasswordFile returns a PasswordProvider reading the password from path on
+// every call, for secrets mounted as files that are updated in place.
+// Surrounding whitespace, such as a trailing newline, is trimmed.
+func PasswordFile(path string) PasswordProvider {
+	return func(context.Context) (string, error) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("read password file: %w", err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+}
+
+// configFields has the fields of Config without its methods, so that String
+// and MarshalJSON can format a redacted copy.
+type configFields Config
+
+func (c Config) redact() configFields {
+	if c.Password != "" {
+		c.Password = redacted
+	}
+	return configFields(c)
+}
+
+// String formats the config with the password redacted.
+func (c Config) String() string {
+	return fmt.Sprintf("%+v", c.redact())
+}
+
+// GoString formats the config for %#v with the password redacted.
+func (c Config) GoString() string {
+	return fmt.Sprintf("connector.Config%+v", c.redact())
+}
+
+// MarshalJSON encodes the config with the password redacted.
+func (c Config) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.redact())
+}
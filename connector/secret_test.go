@@ -0,0 +1,101 @@
+package connector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigRedactsPassword(t *testing.T) {
+	cfg := Config{Host: "db", Username: "app", Password: "hunter2"}
+	cluster := ClusterConfig{Primary: cfg, Replicas: []Config{cfg}, ReadStrategy: "round_robin"}
+
+	data, err := json.Marshal(cfg)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"password":"[REDACTED]"`)
+	assert.Contains(t, string(data), `"host":"db"`)
+
+	clusterData, err := json.Marshal(cluster)
+	require.NoError(t, err)
+
+	for _, out := range []string{
+		cfg.String(),
+		cfg.GoString(),
+		fmt.Sprint(cfg),
+		fmt.Sprintf("%+v", cfg),
+		fmt.Sprintf("%#v", cfg),
+		fmt.Sprintf("%v", &cfg),
+		fmt.Sprintf("%+v", cluster),
+		fmt.Sprintf("%#v", cluster),
+		string(clusterData),
+	} {
+		assert.NotContains(t, out, "hunter2")
+		assert.Contains(t, out, redacted)
+	}
+	assert.Contains(t, cfg.String(), "Password:[REDACTED]")
+	assert.Contains(t, cfg.GoString(), "connector.Config{")
+
+	// Redaction works on a copy, and empty passwords are left empty
+	assert.Equal(t, "hunter2", cfg.Password)
+	assert.NotContains(t, Config{Host: "db"}.String(), redacted)
+}
+
+func TestPasswordFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "password")
+	provider := PasswordFile(path)
+	ctx := context.Background()
+
+	_, err := provider(ctx)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	assert.ErrorContains(t, err, "read password file: ")
+
+	require.NoError(t, os.WriteFile(path, []byte("s3cret\n"), 0o600))
+	password, err := provider(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", password)
+
+	// Rotated secrets are picked up on the next call
+	require.NoError(t, os.WriteFile(path, []byte("  rotated \n"), 0o600))
+	password, err = provider(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "rotated", password)
+}
+
+func TestPoolConfigPasswordProvider(t *testing.T) {
+	t.Setenv("PGPASSFILE", filepath.Join(t.TempDir(), "pgpass"))
+
+	p := &PostgresConnector{config: Config{Host: "db", Username: "app", Password: "static"}}
+	poolCfg, err := p.poolConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "static", poolCfg.ConnConfig.Password)
+	assert.Nil(t, poolCfg.BeforeConnect)
+
+	token := "token1"
+	p.config.PasswordProvider = func(context.Context) (string, error) { return token, nil }
+	poolCfg, err = p.poolConfig()
+	require.NoError(t, err)
+	assert.Empty(t, poolCfg.ConnConfig.Password, "the static password must not be in the DSN")
+	require.NotNil(t, poolCfg.BeforeConnect)
+
+	// Each new connection asks the provider again
+	for _, want := range []string{"token1", "token2"} {
+		token = want
+		cc := poolCfg.ConnConfig.Copy()
+		require.NoError(t, poolCfg.BeforeConnect(context.Background(), cc))
+		assert.Equal(t, want, cc.Password)
+	}
+
+	p.config.PasswordProvider = func(context.Context) (string, error) { return "", errors.New("vault sealed") }
+	poolCfg, err = p.poolConfig()
+	require.NoError(t, err)
+	err = poolCfg.BeforeConnect(context.Background(), &pgx.ConnConfig{})
+	assert.EqualError(t, err, "password provider: vault sealed")
+}
@@ -0,0 +1,169 @@
+package connector
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+
+	"github.com/Konsultn-Engineering/enorm/database"
+	"github.com/Konsultn-Engineering/enorm/dialect"
+)
+
+// SQLConnector is a Connection over a *sql.DB opened by the application, for
+// drivers enorm does not ship with (MySQL, TiDB, SQLite...).
+type SQLConnector struct {
+	db       *sql.DB
+	database *database.SqlDatabase
+	dialect  dialect.Dialect
+}
+
+// FromSQLDB returns a Connection using db, whose SQL is rendered for d. Close
+// closes db.
+//
+// Example:
+//
+//	import _ "github.com/go-sql-driver/mysql"
+//
+//	db, err := sql.Open("mysql", "app:secret@tcp(localhost:3306)/app?parseTime=true")
+//	conn := connector.FromSQLDB(db, dialect.NewMySQLDialect())
+//	e := engine.New(conn)
+func FromSQLDB(db *sql.DB, d dialect.Dialect) *SQLConnector {
+	return &SQLConnector{db: db, database: database.NewSqlDatabase(db), dialect: d}
+}
+
+// SQLDriver returns a Factory for Register that opens a database/sql driver
+// registered under driverName, with the DSN built from Config by dsn. Pool
+// settings are applied and the connection is checked within ConnectTimeout,
+// retrying per Config.Retry. With a Config.PasswordProvider, dsn is called for
+// every new connection, with Password set to the provided one.
+//
+// Example:
+//
+//	connector.Register("mysql", connector.SQLDriver("mysql", dialect.NewMySQLDialect(), func(cfg connector.Config) string {
+//	    return fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?parseTime=true", cfg.Username, cfg.Password, cfg.Host, cfg.Port, cfg.Database)
+//	}))
+//	conn, err := connector.New("mysql", cfg)
+func SQLDriver(driverName string, d dialect.Dialect, dsn func(cfg Config) string) Factory {
+	return func(cfg Config) (Connection, error) {
+		db, err := openSQL(driverName, cfg, dsn)
+		if err != nil {
+			return nil, err
+		}
+		// Zero values keep the database/sql defaults
+		if cfg.Pool.MaxOpen > 0 {
+			db.SetMaxOpenConns(cfg.Pool.MaxOpen)
+		}
+		if cfg.Pool.MaxIdle > 0 {
+			db.SetMaxIdleConns(cfg.Pool.MaxIdle)
+		}
+		db.SetConnMaxLifetime(cfg.Pool.MaxLifetime)
+		db.SetConnMaxIdleTime(cfg.Pool.MaxIdleTime)
+
+		ctx := context.Background()
+		if cfg.ConnectTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, cfg.ConnectTimeout)
+			defer cancel()
+		}
+
+		if cfg.Retry != nil {
+			err = retryConnect(ctx, cfg.Retry, db.PingContext)
+		} else {
+			err = db.PingContext(ctx)
+		}
+		if err != nil {
+			db.Close()
+			return nil, fmt.Errorf("failed to connect to %s: %w", driverName, err)
+		}
+		return FromSQLDB(db, d), nil
+	}
+}
+
+// openSQL opens a *sql.DB for driverName, asking cfg.PasswordProvider for the
+// password of each new connection when set.
+func openSQL(driverName string, cfg Config, dsn func(cfg Config) string) (*sql.DB, error) {
+	if cfg.PasswordProvider == nil {
+		return sql.Open(driverName, dsn(cfg))
+	}
+
+	// sql.Open does not connect; it only looks up the driver
+	probe, err := sql.Open(driverName, "")
+	if err != nil {
+		return nil, err
+	}
+	drv := probe.Driver()
+	probe.Close()
+	return sql.OpenDB(&passwordConnector{driver: drv, cfg: cfg, dsn: dsn}), nil
+}
+
+// passwordConnector is a driver.Connector building the DSN of each new
+// connection with a password from cfg.PasswordProvider.
+type passwordConnector struct {
+	driver driver.Driver
+	cfg    Config
+	dsn    func(cfg Config) string
+}
+
+func (c *passwordConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	password, err := c.cfg.PasswordProvider(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("password provider: %w", err)
+	}
+	cfg := c.cfg
+	cfg.Password = password
+
+	if dc, ok := c.driver.(driver.DriverContext); ok {
+		connector, err := dc.OpenConnector(c.dsn(cfg))
+		if err != nil {
+			return nil, err
+		}
+		return connector.Connect(ctx)
+	}
+	return c.driver.Open(c.dsn(cfg))
+}
+
+func (c *passwordConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// DB returns the wrapped *sql.DB.
+func (c *SQLConnector) DB() *sql.DB {
+	return c.db
+}
+
+// Database returns a database abstraction interface.
+func (c *SQLConnector) Database() database.Database {
+	return c.database
+}
+
+// Dialect returns the dialect given to FromSQLDB.
+func (c *SQLConnector) Dialect() dialect.Dialect {
+	return c.dialect
+}
+
+// Health checks the connection health.
+func (c *SQLConnector) Health(ctx context.Context) error {
+	return c.db.PingContext(ctx)
+}
+
+// Stats returns connection pool statistics. database/sql only tracks time
+// spent waiting for a connection, reported as AcquireDuration.
+func (c *SQLConnector) Stats() ConnectionStats {
+	s := c.db.Stats()
+	return ConnectionStats{
+		OpenConnections:         s.OpenConnections,
+		InUse:                   s.InUse,
+		Idle:                    s.Idle,
+		MaxOpen:                 s.MaxOpenConnections,
+		AcquireDuration:         s.WaitDuration,
+		EmptyAcquireCount:       s.WaitCount,
+		MaxLifetimeDestroyCount: s.MaxLifetimeClosed,
+		MaxIdleDestroyCount:     s.MaxIdleTimeClosed,
+	}
+}
+
+// Close closes the wrapped *sql.DB.
+func (c *SQLConnector) Close() error {
+	return c.db.Close()
+}
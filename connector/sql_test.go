@@ -0,0 +1,134 @@
+package connector
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Konsultn-Engineering/enorm/dialect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	sql.Register("enormstub", stubDriver{})
+}
+
+// stubDriver is a database/sql driver whose connections can only be opened
+// and closed. DSNs starting with "down" fail to connect.
+type stubDriver struct{}
+
+// stubOpened records the DSN of every connection stubDriver opens.
+var stubOpened struct {
+	sync.Mutex
+	dsns []string
+}
+
+func (stubDriver) Open(dsn string) (driver.Conn, error) {
+	if strings.HasPrefix(dsn, "down") {
+		return nil, errors.New("connection refused")
+	}
+	stubOpened.Lock()
+	stubOpened.dsns = append(stubOpened.dsns, dsn)
+	stubOpened.Unlock()
+	return stubDriverConn{}, nil
+}
+
+type stubDriverConn struct{}
+
+func (stubDriverConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (stubDriverConn) Close() error                        { return nil }
+func (stubDriverConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+// stubFactory opens the stub driver with Config.Host as DSN.
+var stubFactory = SQLDriver("enormstub", dialect.NewMySQLDialect(), func(cfg Config) string { return cfg.Host })
+
+func TestSQLDriver(t *testing.T) {
+	conn, err := stubFactory(Config{Host: "ok", Pool: PoolConfig{MaxOpen: 7}})
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.IsType(t, &SQLConnector{}, conn)
+	assert.Equal(t, "mysql", conn.Dialect().Name())
+	assert.NotNil(t, conn.Database())
+	assert.Equal(t, 7, conn.Stats().MaxOpen)
+	assert.NoError(t, conn.Health(context.Background()))
+	assert.Same(t, conn.DB(), conn.DB())
+}
+
+func TestSQLDriverConnectFailure(t *testing.T) {
+	_, err := stubFactory(Config{Host: "down"})
+	assert.EqualError(t, err, "failed to connect to enormstub: connection refused")
+}
+
+func TestSQLDriverPasswordProvider(t *testing.T) {
+	stubOpened.Lock()
+	stubOpened.dsns = nil
+	stubOpened.Unlock()
+
+	factory := SQLDriver("enormstub", dialect.NewMySQLDialect(), func(cfg Config) string {
+		return cfg.Host + "/" + cfg.Password
+	})
+	var calls atomic.Int32
+	conn, err := factory(Config{Host: "ok", Password: "static", PasswordProvider: func(context.Context) (string, error) {
+		return fmt.Sprint("token", calls.Add(1)), nil
+	}})
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// The ping opened a first connection; holding it forces a second one
+	ctx := context.Background()
+	first, err := conn.DB().Conn(ctx)
+	require.NoError(t, err)
+	second, err := conn.DB().Conn(ctx)
+	require.NoError(t, err)
+	first.Close()
+	second.Close()
+
+	stubOpened.Lock()
+	assert.Equal(t, []string{"ok/token1", "ok/token2"}, stubOpened.dsns)
+	stubOpened.Unlock()
+
+	_, err = factory(Config{Host: "ok", PasswordProvider: func(context.Context) (string, error) {
+		return "", errors.New("vault sealed")
+	}})
+	assert.EqualError(t, err, "failed to connect to enormstub: password provider: vault sealed")
+}
+
+func TestSQLConnectorStats(t *testing.T) {
+	db, err := sql.Open("enormstub", "ok")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	c := FromSQLDB(db, dialect.NewMySQLDialect())
+	defer c.Close()
+
+	ctx := context.Background()
+	held, err := db.Conn(ctx)
+	require.NoError(t, err)
+
+	s := c.Stats()
+	assert.Equal(t, 1, s.OpenConnections)
+	assert.Equal(t, 1, s.InUse)
+	assert.Equal(t, 0, s.Idle)
+	assert.Equal(t, 1, s.MaxOpen)
+
+	// Waiting for the only connection counts as an empty acquire
+	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err = db.Conn(waitCtx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	require.NoError(t, held.Close())
+	s = c.Stats()
+	assert.Equal(t, 0, s.InUse)
+	assert.Equal(t, 1, s.Idle)
+	assert.Equal(t, int64(1), s.EmptyAcquireCount)
+	assert.Positive(t, s.AcquireDuration)
+}
@@ -1,8 +1,38 @@
 package connector
 
+import "time"
+
 // ConnectionStats represents database connection pool statistics.
 type ConnectionStats struct {
 	OpenConnections int
 	InUse           int
 	Idle            int
+	MaxOpen         int
+
+	// Cumulative since the pool was opened
+	AcquireCount            int64         // Connections handed out by the pool
+	AcquireDuration         time.Duration // Total time spent acquiring connections
+	CanceledAcquireCount    int64         // Acquires abandoned because their context was canceled
+	EmptyAcquireCount       int64         // Acquires that had to wait for a connection
+	NewConnections          int64         // Physical connections opened
+	MaxLifetimeDestroyCount int64         // Connections closed for exceeding MaxLifetime
+	MaxIdleDestroyCount     int64         // Connections closed for exceeding MaxIdleTime
+
+	// Health of each member, for clusters
+	Members []MemberState
+}
+
+// add accumulates o into s, for cluster-wide totals.
+func (s *ConnectionStats) add(o ConnectionStats) {
+	s.OpenConnections += o.OpenConnections
+	s.InUse += o.InUse
+	s.Idle += o.Idle
+	s.MaxOpen += o.MaxOpen
+	s.AcquireCount += o.AcquireCount
+	s.AcquireDuration += o.AcquireDuration
+	s.CanceledAcquireCount += o.CanceledAcquireCount
+	s.EmptyAcquireCount += o.EmptyAcquireCount
+	s.NewConnections += o.NewConnections
+	s.MaxLifetimeDestroyCount += o.MaxLifetimeDestroyCount
+	s.MaxIdleDestroyCount += o.MaxIdleDestroyCount
 }
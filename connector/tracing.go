@@ -0,0 +1,40 @@
+package connector
+
+import (
+	"context"
+
+	"github.com/Konsultn-Engineering/enorm/trace"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type acquireSpanKey struct{}
+
+// pgxTracer reports pgxpool connection acquisition to a trace.Tracer. Query
+// spans are left to the engine (see engine.WithTracer), so the pgx.QueryTracer
+// methods, required to install it on the pool, are no-ops.
+type pgxTracer struct {
+	tracer trace.Tracer
+}
+
+var (
+	_ pgx.QueryTracer       = pgxTracer{}
+	_ pgxpool.AcquireTracer = pgxTracer{}
+)
+
+func (t pgxTracer) TraceAcquireStart(ctx context.Context, _ *pgxpool.Pool, _ pgxpool.TraceAcquireStartData) context.Context {
+	ctx, span := t.tracer.Start(ctx, "ACQUIRE", trace.String(trace.DBSystem, "postgresql"))
+	return context.WithValue(ctx, acquireSpanKey{}, span)
+}
+
+func (t pgxTracer) TraceAcquireEnd(ctx context.Context, _ *pgxpool.Pool, data pgxpool.TraceAcquireEndData) {
+	if span, ok := ctx.Value(acquireSpanKey{}).(trace.Span); ok {
+		trace.End(span, data.Err)
+	}
+}
+
+func (pgxTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, _ pgx.TraceQueryStartData) context.Context {
+	return ctx
+}
+
+func (pgxTracer) TraceQueryEnd(context.Context, *pgx.Conn, pgx.TraceQueryEndData) {}
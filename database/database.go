@@ -13,6 +13,8 @@ type Database interface {
 	QueryContext(ctx context.Context, query string, args ...any) (Rows, error)
 	// Exec executes a query without returning rows, such as INSERT or UPDATE.
 	Exec(query string, args ...any) (Result, error)
+	// ExecContext executes a query without returning rows with a context.
+	ExecContext(ctx context.Context, query string, args ...any) (Result, error)
 	// PingContext verifies a connection to the database is still alive.
 	PingContext(ctx context.Context) error
 	// Close closes the database, releasing any open resources.
@@ -25,6 +27,40 @@ type Database interface {
 	Prepare(query string) (*sql.Stmt, error)
 }
 
+// Querier runs statements. It is implemented by both Database and Tx, so the
+// same code can run inside or outside a transaction.
+type Querier interface {
+	Query(query string, args ...any) (Rows, error)
+	QueryContext(ctx context.Context, query string, args ...any) (Rows, error)
+	Exec(query string, args ...any) (Result, error)
+	ExecContext(ctx context.Context, query string, args ...any) (Result, error)
+}
+
+// Tx is an open database transaction. It must be ended with Commit or Rollback.
+type Tx interface {
+	Querier
+	// Commit makes the transaction's changes permanent.
+	Commit() error
+	// Rollback discards the transaction's changes.
+	Rollback() error
+}
+
+// Beginner is implemented by databases that support transactions.
+type Beginner interface {
+	// BeginTx starts a transaction. ctx applies to the whole transaction.
+	BeginTx(ctx context.Context) (Tx, error)
+}
+
+// Copier is implemented by databases that support bulk loading through the
+// PostgreSQL COPY protocol.
+type Copier interface {
+	// CopyFrom streams rows into table, qualified with schema unless it is
+	// empty. next returns one row of values per call,
+	// matching columns, and signals the end of data by returning a nil row.
+	// A non-nil error from next aborts the copy.
+	CopyFrom(ctx context.Context, schema, table string, columns []string, next func() ([]any, error)) (int64, error)
+}
+
 // Rows provides an abstract interface for iterating over database rows.
 type Rows interface {
 	// Next prepares the next result row for reading.
@@ -43,11 +43,35 @@ func (p *PgxDatabase) Exec(query string, args ...any) (Result, error) {
 	return &PgxResult{cmdTag: cmdTag}, err
 }
 
+// ExecContext executes a query without returning rows with a context.
+func (p *PgxDatabase) ExecContext(ctx context.Context, query string, args ...any) (Result, error) {
+	cmdTag, err := p.pool.Exec(ctx, query, args...)
+	return &PgxResult{cmdTag: cmdTag}, err
+}
+
+// BeginTx starts a transaction.
+func (p *PgxDatabase) BeginTx(ctx context.Context) (Tx, error) {
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &PgxTx{tx: tx, ctx: ctx}, nil
+}
+
 // PingContext verifies the connection to the database is alive.
 func (p *PgxDatabase) PingContext(ctx context.Context) error {
 	return p.pool.Ping(ctx)
 }
 
+// CopyFrom bulk-loads rows into table using the COPY protocol.
+func (p *PgxDatabase) CopyFrom(ctx context.Context, schema, table string, columns []string, next func() ([]any, error)) (int64, error) {
+	ident := pgx.Identifier{table}
+	if schema != "" {
+		ident = pgx.Identifier{schema, table}
+	}
+	return p.pool.CopyFrom(ctx, ident, columns, pgx.CopyFromFunc(next))
+}
+
 // Close closes the database.
 func (p *PgxDatabase) Close() error {
 	p.pool.Close()
@@ -65,6 +89,52 @@ func (p *PgxDatabase) Prepare(query string) (*sql.Stmt, error) {
 	return nil, fmt.Errorf("Prepare not supported with pgxpool - queries are automatically prepared")
 }
 
+// PgxTx implements Tx and Copier for pgx.Tx.
+type PgxTx struct {
+	tx  pgx.Tx
+	ctx context.Context // Context the transaction was started with, used by Commit and Rollback
+}
+
+// Query executes a query that returns rows within the transaction.
+func (t *PgxTx) Query(query string, args ...any) (Rows, error) {
+	return t.QueryContext(t.ctx, query, args...)
+}
+
+// QueryContext executes a query with a context within the transaction.
+func (t *PgxTx) QueryContext(ctx context.Context, query string, args ...any) (Rows, error) {
+	rows, err := t.tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return &PgxRows{rows: rows}, nil
+}
+
+// Exec executes a query without returning rows within the transaction.
+func (t *PgxTx) Exec(query string, args ...any) (Result, error) {
+	return t.ExecContext(t.ctx, query, args...)
+}
+
+// ExecContext executes a query without returning rows with a context within the transaction.
+func (t *PgxTx) ExecContext(ctx context.Context, query string, args ...any) (Result, error) {
+	cmdTag, err := t.tx.Exec(ctx, query, args...)
+	return &PgxResult{cmdTag: cmdTag}, err
+}
+
+// CopyFrom bulk-loads rows into table using the COPY protocol within the transaction.
+func (t *PgxTx) CopyFrom(ctx context.Context, schema, table string, columns []string, next func() ([]any, error)) (int64, error) {
+	ident := pgx.Identifier{table}
+	if schema != "" {
+		ident = pgx.Identifier{schema, table}
+	}
+	return t.tx.CopyFrom(ctx, ident, columns, pgx.CopyFromFunc(next))
+}
+
+// Commit commits the transaction.
+func (t *PgxTx) Commit() error { return t.tx.Commit(t.ctx) }
+
+// Rollback aborts the transaction.
+func (t *PgxTx) Rollback() error { return t.tx.Rollback(t.ctx) }
+
 // PgxRows implements Rows for pgx.Rows.
 type PgxRows struct {
 	rows              pgx.Rows
@@ -114,3 +184,6 @@ func (r *PgxResult) RowsAffected() (int64, error) {
 
 // Assert that PgxDatabase implements the Database interface.
 var _ Database = (*PgxDatabase)(nil)
+
+// Assert that PgxDatabase supports transactions.
+var _ Beginner = (*PgxDatabase)(nil)
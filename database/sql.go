@@ -39,6 +39,20 @@ func (s *SqlDatabase) Exec(query string, args ...any) (Result, error) {
 	return res, err // database/sql.Result implements Result
 }
 
+// ExecContext executes a query without returning rows with a context.
+func (s *SqlDatabase) ExecContext(ctx context.Context, query string, args ...any) (Result, error) {
+	return s.db.ExecContext(ctx, query, args...)
+}
+
+// BeginTx starts a transaction.
+func (s *SqlDatabase) BeginTx(ctx context.Context) (Tx, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &SqlTx{tx: tx}, nil
+}
+
 // PingContext verifies the connection to the database is alive.
 func (s *SqlDatabase) PingContext(ctx context.Context) error {
 	return s.db.PingContext(ctx)
@@ -56,6 +70,41 @@ func (s *SqlDatabase) SetMaxIdleConns(n int) { s.db.SetMaxIdleConns(n) }
 // Prepare creates a prepared statement for later queries or executions.
 func (s *SqlDatabase) Prepare(query string) (*sql.Stmt, error) { return s.db.Prepare(query) }
 
+// SqlTx implements Tx for *sql.Tx.
+type SqlTx struct {
+	tx *sql.Tx
+}
+
+// Query executes a query that returns rows within the transaction.
+func (t *SqlTx) Query(query string, args ...any) (Rows, error) {
+	return t.QueryContext(context.Background(), query, args...)
+}
+
+// QueryContext executes a query with a context within the transaction.
+func (t *SqlTx) QueryContext(ctx context.Context, query string, args ...any) (Rows, error) {
+	rows, err := t.tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return &SqlRows{rows: rows}, nil
+}
+
+// Exec executes a query without returning rows within the transaction.
+func (t *SqlTx) Exec(query string, args ...any) (Result, error) {
+	return t.tx.Exec(query, args...)
+}
+
+// ExecContext executes a query without returning rows with a context within the transaction.
+func (t *SqlTx) ExecContext(ctx context.Context, query string, args ...any) (Result, error) {
+	return t.tx.ExecContext(ctx, query, args...)
+}
+
+// Commit commits the transaction.
+func (t *SqlTx) Commit() error { return t.tx.Commit() }
+
+// Rollback aborts the transaction.
+func (t *SqlTx) Rollback() error { return t.tx.Rollback() }
+
 // SqlRows implements Rows for *sql.Rows.
 type SqlRows struct {
 	rows *sql.Rows
@@ -79,3 +128,6 @@ func (s *SqlRows) Values() ([]any, error) { return []any{}, nil }
 
 // Assert that SqlDatabase implements the Database interface.
 var _ Database = (*SqlDatabase)(nil)
+
+// Assert that SqlDatabase supports transactions.
+var _ Beginner = (*SqlDatabase)(nil)
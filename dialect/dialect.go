@@ -1,8 +1,19 @@
 package dialect
 
 type Dialect interface {
+	// Name identifies the database system, using the OpenTelemetry db.system
+	// values ("postgresql", "mysql", "tidb").
+	Name() string
 	QuoteIdentifier(name string) string
 	Placeholder(n int) string
 	RenderValue(v any) string
 	SupportsVector() bool
+	// SupportsOnConflict reports whether upserts use ON CONFLICT (PostgreSQL)
+	// rather than ON DUPLICATE KEY UPDATE (MySQL/TiDB).
+	SupportsOnConflict() bool
+	// SupportsReturning reports whether INSERT ... RETURNING is available.
+	SupportsReturning() bool
+	// SupportsUpdateFrom reports whether UPDATE accepts a FROM clause for
+	// joining against other sources, such as an inline VALUES list.
+	SupportsUpdateFrom() bool
 }
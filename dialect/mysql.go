@@ -14,6 +14,10 @@ func NewMySQLDialect() Dialect {
 	return &MySQL{}
 }
 
+func (MySQL) Name() string {
+	return "mysql"
+}
+
 func (m MySQL) QuoteIdentifier(name string) string {
 	return "`" + name + "`"
 }
@@ -51,3 +55,15 @@ func (m MySQL) RenderValue(v any) string {
 func (m MySQL) SupportsVector() bool {
 	return false
 }
+
+func (m MySQL) SupportsOnConflict() bool {
+	return false
+}
+
+func (m MySQL) SupportsReturning() bool {
+	return false
+}
+
+func (m MySQL) SupportsUpdateFrom() bool {
+	return false
+}
@@ -14,6 +14,10 @@ func NewPostgresDialect() Dialect {
 	return &Postgres{}
 }
 
+func (Postgres) Name() string {
+	return "postgresql"
+}
+
 func (p Postgres) QuoteIdentifier(name string) string {
 	return `"` + name + `"`
 }
@@ -51,3 +55,15 @@ func (Postgres) RenderValue(v any) string {
 func (p Postgres) SupportsVector() bool {
 	return true
 }
+
+func (p Postgres) SupportsOnConflict() bool {
+	return true
+}
+
+func (p Postgres) SupportsReturning() bool {
+	return true
+}
+
+func (p Postgres) SupportsUpdateFrom() bool {
+	return true
+}
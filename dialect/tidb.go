@@ -10,6 +10,10 @@ func NewTiDBDialect() Dialect {
 	}
 }
 
+func (t *TiDB) Name() string {
+	return "tidb"
+}
+
 func (t *TiDB) SupportsVector() bool {
 	return true
 }
@@ -0,0 +1,255 @@
+package engine
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"time"
+	"unsafe"
+
+	"github.com/Konsultn-Engineering/enorm/ast"
+	"github.com/Konsultn-Engineering/enorm/schema"
+	"github.com/google/uuid"
+)
+
+// bulkAlias names the VALUES list joined by a PostgreSQL bulk update.
+const bulkAlias = "v"
+
+// BulkUpdate writes per-row values for many entities ([]*T, []T or a pointer
+// to either) in as few statements as the bind parameter limit allows (see
+// WithMaxParams). Rows are matched by primary key; when columns are given
+// (column or Go field names), only those are written. auto_now columns are
+// always written with the current time. BeforeUpdate and AfterUpdate hooks run
+// for every entity.
+//
+// PostgreSQL joins against an inline VALUES list:
+//
+//	UPDATE "users" SET "name" = "v"."name"
+//	FROM (VALUES (CAST($1 AS bigint), CAST($2 AS text)), ($3, $4)) AS "v"("id", "name")
+//	WHERE "users"."id" = "v"."id"
+//
+// MySQL and TiDB use a CASE expression per column:
+//
+//	UPDATE `users` SET `name` = CASE `id` WHEN ? THEN ? WHEN ? THEN ? ELSE `name` END
+//	WHERE `id` IN (?, ?)
+//
+// Returns the SQL of the last executed statement.
+func (e *Engine) BulkUpdate(entities any, columns ...string) (string, error) {
+	defer e.reset()
+	meta, rows, err := e.entityBatch(entities)
+	if err != nil {
+		return "", err
+	}
+
+	fields, err := updateFields(meta, columns)
+	if err != nil {
+		return "", err
+	}
+
+	keys := make([][]any, len(rows))
+	for i, ptr := range rows {
+		if keys[i], err = meta.KeyValues(ptr); err != nil {
+			return "", fmt.Errorf("entity %d: %w", i, err)
+		}
+	}
+
+	var queryStr string
+	err = e.withHooks(meta, schema.HookBeforeUpdate|schema.HookAfterUpdate, func(ctx context.Context) error {
+		for i, ptr := range rows {
+			if err := meta.RunHook(ctx, schema.HookBeforeUpdate, ptr); err != nil {
+				return fmt.Errorf("entity %d: %w", i, err)
+			}
+			if err := e.stampTenant(meta, ptr); err != nil {
+				return fmt.Errorf("entity %d: %w", i, err)
+			}
+			fields = appendMissing(fields, e.schema.PrepareUpdate(meta, ptr))
+		}
+		for i, ptr := range rows {
+			if err := e.schema.Validate(meta, ptr, fields...); err != nil {
+				return fmt.Errorf("entity %d: %w", i, err)
+			}
+		}
+
+		keyCount := len(meta.PrimaryKey)
+		perRow := keyCount + len(fields)
+		if !e.dialect.SupportsUpdateFrom() {
+			// One WHEN/THEN pair per column plus the key in the IN list
+			perRow = len(fields)*(keyCount+1) + keyCount
+		}
+		chunkSize := max(e.maxParams/perRow, 1)
+
+		for start := 0; start < len(rows); start += chunkSize {
+			end := min(start+chunkSize, len(rows))
+
+			var stmt *ast.UpdateStmt
+			if e.dialect.SupportsUpdateFrom() {
+				stmt = bulkUpdateFrom(e.table(meta), meta, fields, rows[start:end], keys[start:end])
+			} else {
+				stmt = bulkUpdateCase(e.table(meta), meta, fields, rows[start:end], keys[start:end])
+			}
+			if err := e.scopeTenant(stmt, meta); err != nil {
+				stmt.Release()
+				return err
+			}
+
+			var err error
+			queryStr, _, err = e.exec(stmt)
+			stmt.Release()
+			if err != nil {
+				return err
+			}
+		}
+
+		for i, ptr := range rows {
+			if err := meta.RunHook(ctx, schema.HookAfterUpdate, ptr); err != nil {
+				return fmt.Errorf("entity %d: %w", i, err)
+			}
+		}
+		return nil
+	})
+	return queryStr, err
+}
+
+// bulkUpdateFrom builds UPDATE ... SET col = v.col FROM (VALUES ...) AS v(...)
+// WHERE t.key = v.key.
+func bulkUpdateFrom(table *ast.Table, meta *schema.EntityMeta, fields []*schema.FieldMeta, rows []unsafe.Pointer, keys [][]any) *ast.UpdateStmt {
+	values := ast.NewValuesTable(bulkAlias)
+	for _, fm := range meta.PrimaryKey {
+		values.Columns = append(values.Columns, fm.DBName)
+		values.Types = append(values.Types, castType(fm))
+	}
+	for _, fm := range fields {
+		values.Columns = append(values.Columns, fm.DBName)
+		values.Types = append(values.Types, castType(fm))
+	}
+
+	for i, ptr := range rows {
+		row := make([]ast.Node, 0, len(values.Columns))
+		for _, k := range keys[i] {
+			row = append(row, ast.NewValue(k))
+		}
+		for _, fm := range fields {
+			row = append(row, ast.NewValue(fm.ValueOf(ptr)))
+		}
+		values.AddRow(row...)
+	}
+
+	stmt := ast.NewUpdateStmt(table)
+	for _, fm := range fields {
+		stmt.AddSet(fm.DBName, ast.NewColumn(bulkAlias, fm.DBName, ""))
+	}
+	stmt.From = values
+	for _, fm := range meta.PrimaryKey {
+		stmt.AddWhereCondition(ast.NewBinaryExpr(
+			ast.NewColumn(meta.TableName, fm.DBName, ""),
+			ast.OpEqual,
+			ast.NewColumn(bulkAlias, fm.DBName, ""),
+		), ast.OpAnd)
+	}
+	return stmt
+}
+
+// bulkUpdateCase builds UPDATE ... SET col = CASE key WHEN ... THEN ... END
+// WHERE key IN (...). Composite keys use a searched CASE on row values.
+func bulkUpdateCase(table *ast.Table, meta *schema.EntityMeta, fields []*schema.FieldMeta, rows []unsafe.Pointer, keys [][]any) *ast.UpdateStmt {
+	single := len(meta.PrimaryKey) == 1
+	stmt := ast.NewUpdateStmt(table)
+
+	for _, fm := range fields {
+		var expr *ast.CaseExpr
+		if single {
+			expr = ast.NewCaseExpr(ast.NewColumn("", meta.PrimaryKey[0].DBName, ""))
+		} else {
+			expr = ast.NewCaseExpr(nil)
+		}
+
+		for i, ptr := range rows {
+			var cond ast.Node
+			if single {
+				cond = ast.NewValue(keys[i][0])
+			} else {
+				cond = keyCondition(meta, keys[i])
+			}
+			expr.AddWhen(cond, ast.NewValue(fm.ValueOf(ptr)))
+		}
+		expr.Else = ast.NewColumn("", fm.DBName, "")
+		stmt.AddSet(fm.DBName, expr)
+	}
+
+	keyList := make([]ast.Node, len(rows))
+	for i := range rows {
+		if single {
+			keyList[i] = ast.NewValue(keys[i][0])
+			continue
+		}
+		vals := make([]ast.Node, len(keys[i]))
+		for j, k := range keys[i] {
+			vals[j] = ast.NewValue(k)
+		}
+		keyList[i] = ast.NewTuple(vals...)
+	}
+
+	var target ast.Node
+	if single {
+		target = ast.NewColumn("", meta.PrimaryKey[0].DBName, "")
+	} else {
+		cols := make([]ast.Node, len(meta.PrimaryKey))
+		for i, fm := range meta.PrimaryKey {
+			cols[i] = ast.NewColumn("", fm.DBName, "")
+		}
+		target = ast.NewTuple(cols...)
+	}
+	stmt.AddWhereCondition(ast.NewBinaryExpr(target, ast.OpIn, ast.NewTuple(keyList...)), ast.OpAnd)
+
+	return stmt
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	nullTimeType = reflect.TypeOf(sql.NullTime{})
+	uuidType     = reflect.TypeOf(uuid.UUID{})
+)
+
+// castType returns the SQL type a VALUES column is cast to, so PostgreSQL
+// does not infer text for bind parameters. Uses the `type` tag when present;
+// returns "" for types without an obvious mapping.
+func castType(fm *schema.FieldMeta) string {
+	if fm.DBType != "" {
+		return fm.DBType
+	}
+
+	t := fm.Type
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t {
+	case timeType, nullTimeType:
+		return "timestamptz"
+	case uuidType:
+		return "uuid"
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return "bigint"
+	case reflect.Uint, reflect.Uint64:
+		// Values above math.MaxInt64 overflow bigint
+		return "numeric"
+	case reflect.Float32:
+		return "real"
+	case reflect.Float64:
+		return "double precision"
+	case reflect.String:
+		return "text"
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "bytea"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,80 @@
+package engine
+
+import (
+	"math"
+	"testing"
+
+	"github.com/Konsultn-Engineering/enorm/dialect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Account struct {
+	ID      int64
+	Name    string
+	Balance float64
+}
+
+func TestBulkUpdatePostgres(t *testing.T) {
+	conn := newFakeConn(dialect.NewPostgresDialect())
+	e := New(conn)
+
+	accounts := []*Account{{ID: 1, Name: "a", Balance: 10}, {ID: 2, Name: "b", Balance: 20}}
+	_, err := e.BulkUpdate(accounts, "Name")
+	require.NoError(t, err)
+
+	require.Len(t, conn.db.queries, 1)
+	assert.Equal(t, `UPDATE "accounts" SET "name" = "v"."name" `+
+		`FROM (VALUES (CAST($1 AS bigint), CAST($2 AS text)), ($3, $4)) AS "v"("id", "name") `+
+		`WHERE "accounts"."id" = "v"."id"`, conn.db.queries[0])
+	assert.Equal(t, []any{int64(1), "a", int64(2), "b"}, conn.db.args[0])
+}
+
+type Counter struct {
+	ID   int64
+	Hits uint64
+}
+
+func TestBulkUpdateCastsUint64ToNumeric(t *testing.T) {
+	conn := newFakeConn(dialect.NewPostgresDialect())
+	e := New(conn)
+
+	counters := []*Counter{{ID: 1, Hits: math.MaxUint64}, {ID: 2, Hits: 3}}
+	_, err := e.BulkUpdate(counters, "Hits")
+	require.NoError(t, err)
+
+	require.Len(t, conn.db.queries, 1)
+	assert.Contains(t, conn.db.queries[0], `(VALUES (CAST($1 AS bigint), CAST($2 AS numeric)), ($3, $4))`)
+	assert.Equal(t, uint64(math.MaxUint64), conn.db.args[0][1])
+}
+
+func TestBulkUpdateMySQL(t *testing.T) {
+	conn := newFakeConn(dialect.NewMySQLDialect())
+	e := New(conn)
+
+	accounts := []Account{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}}
+	_, err := e.BulkUpdate(accounts, "name")
+	require.NoError(t, err)
+
+	require.Len(t, conn.db.queries, 1)
+	assert.Equal(t, "UPDATE `accounts` SET `name` = CASE `id` WHEN ? THEN ? WHEN ? THEN ? ELSE `name` END "+
+		"WHERE `id` IN (?, ?)", conn.db.queries[0])
+	assert.Equal(t, []any{int64(1), "a", int64(2), "b", int64(1), int64(2)}, conn.db.args[0])
+}
+
+func TestBulkUpdateChunksByParamLimit(t *testing.T) {
+	conn := newFakeConn(dialect.NewPostgresDialect())
+	e := New(conn, WithMaxParams(4))
+
+	accounts := []*Account{{ID: 1}, {ID: 2}, {ID: 3}}
+	_, err := e.BulkUpdate(accounts, "name")
+	require.NoError(t, err)
+
+	// Two parameters per row: two rows per statement
+	require.Len(t, conn.db.queries, 2)
+	assert.Len(t, conn.db.args[0], 4)
+	assert.Len(t, conn.db.args[1], 2)
+
+	// Chunks embed their rows' values and must not fill the query cache
+	assert.Zero(t, e.qcache.Stats().Entries)
+}
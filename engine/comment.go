@@ -0,0 +1,88 @@
+package engine
+
+import (
+	"context"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+type sqlTagsKey struct{}
+
+// ContextWithSQLTags returns a copy of ctx carrying tags for WithSQLComment,
+// merged over any tags ctx already carries.
+//
+// Example:
+//
+//	ctx = engine.ContextWithSQLTags(r.Context(), map[string]string{
+//	    "route":       "/users/{id}",
+//	    "traceparent": r.Header.Get("traceparent"),
+//	})
+//	e.WithContext(ctx).FindOne(&user)
+func ContextWithSQLTags(ctx context.Context, tags map[string]string) context.Context {
+	merged := maps.Clone(SQLTagsFromContext(ctx))
+	if merged == nil {
+		merged = make(map[string]string, len(tags))
+	}
+	maps.Copy(merged, tags)
+	return context.WithValue(ctx, sqlTagsKey{}, merged)
+}
+
+// SQLTagsFromContext returns the tags stored by ContextWithSQLTags.
+func SQLTagsFromContext(ctx context.Context) map[string]string {
+	tags, _ := ctx.Value(sqlTagsKey{}).(map[string]string)
+	return tags
+}
+
+// WithSQLComment appends a sqlcommenter comment to every statement, so that
+// entries in pg_stat_statements or the slow query log can be traced back to
+// the code that issued them:
+//
+//	SELECT ... /*app='billing',route='%2Fusers%2F%7Bid%7D',traceparent='00-...'*/
+//
+// The comment holds app and the tags of the statement's context (see
+// ContextWithSQLTags), sorted by key and URL-encoded. It is appended after
+// rendering, so the query cache is shared by all tag values.
+func WithSQLComment(app string) Option {
+	return func(e *Engine) {
+		e.Use(func(next Handler) Handler {
+			return func(ctx context.Context, st *Statement) error {
+				if comment := sqlComment(app, SQLTagsFromContext(ctx)); comment != "" && st.SQL != "" {
+					st.SQL += " " + comment
+				}
+				return next(ctx, st)
+			}
+		})
+	}
+}
+
+// sqlComment formats app and tags as a sqlcommenter comment, or returns ""
+// when there is nothing to record. A context "app" tag overrides app.
+func sqlComment(app string, tags map[string]string) string {
+	if tags["app"] == "" && app != "" {
+		tags = maps.Clone(tags)
+		if tags == nil {
+			tags = make(map[string]string, 1)
+		}
+		tags["app"] = app
+	}
+
+	var sb strings.Builder
+	for _, k := range slices.Sorted(maps.Keys(tags)) {
+		if tags[k] == "" {
+			continue
+		}
+		if sb.Len() > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(url.PathEscape(k))
+		sb.WriteString("='")
+		sb.WriteString(url.PathEscape(tags[k]))
+		sb.WriteByte('\'')
+	}
+	if sb.Len() == 0 {
+		return ""
+	}
+	return "/*" + sb.String() + "*/"
+}
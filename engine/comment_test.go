@@ -0,0 +1,31 @@
+package engine
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Konsultn-Engineering/enorm/dialect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLComment(t *testing.T) {
+	conn := newFakeConn(dialect.NewPostgresDialect())
+	e := New(conn, WithSQLComment("billing"))
+
+	ctx := ContextWithSQLTags(context.Background(), map[string]string{"route": "/users/{id}"})
+	ctx = ContextWithSQLTags(ctx, map[string]string{"traceparent": "00-4bf92f3577b34da6-00f067aa0ba902b7-01"})
+	queryStr, err := e.WithContext(ctx).WhereEq("title", "a").Find(&[]*Note{})
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT "notes"."id", "notes"."title", "notes"."deleted_at" FROM "notes" WHERE "title" = $1 AND "notes"."deleted_at" IS NULL `+
+		`/*app='billing',route='%2Fusers%2F%7Bid%7D',traceparent='00-4bf92f3577b34da6-00f067aa0ba902b7-01'*/`, queryStr)
+	assert.Equal(t, queryStr, conn.db.queries[0])
+
+	// The rendered statement is cached without the comment
+	ctx = ContextWithSQLTags(context.Background(), map[string]string{"route": "/admin"})
+	_, err = e.WithContext(ctx).WhereEq("title", "b").Find(&[]*Note{})
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(conn.db.queries[1], `IS NULL /*app='billing',route='%2Fadmin'*/`))
+	assert.Equal(t, []any{"b"}, conn.db.args[1])
+}
@@ -0,0 +1,187 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"reflect"
+	"unsafe"
+
+	"github.com/Konsultn-Engineering/enorm/database"
+	"github.com/Konsultn-Engineering/enorm/schema"
+)
+
+// maxBindParams is the default bind parameter limit of a single statement
+// (65535 on both PostgreSQL and MySQL). See WithMaxParams.
+const maxBindParams = 65535
+
+// CopyFrom bulk-loads entities ([]*T, []T or a pointer to either) and returns
+// the number of rows written. ID generators and timestamps are applied to every
+// entity, and each one is validated, before it is sent.
+//
+// On pgx-backed databases rows are streamed with the COPY protocol; other
+// drivers fall back to multi-row INSERTs chunked below the bind parameter limit
+// (see WithMaxParams), run in a single transaction. On a database without
+// transactions a failed fallback load keeps the chunks already written, and
+// their rows are counted in the returned total.
+// Database-generated keys are not read back, and lifecycle hooks are not run.
+//
+// Example:
+//
+//	n, err := e.CopyFrom(ctx, events)
+func (e *Engine) CopyFrom(ctx context.Context, entities any) (int64, error) {
+	defer e.reset()
+	meta, rows, err := e.entityBatch(entities)
+	if err != nil {
+		return 0, err
+	}
+
+	i := 0
+	return e.copyRows(ctx, meta, func() (unsafe.Pointer, error) {
+		if i == len(rows) {
+			return nil, nil
+		}
+		i++
+		return rows[i-1], nil
+	})
+}
+
+// CopyFromSeq is the streaming variant of Engine.CopyFrom: entities are pulled
+// from seq one at a time, so the full data set never has to be held in memory.
+//
+// Example:
+//
+//	n, err := engine.CopyFromSeq(ctx, e, func(yield func(*Event) bool) {
+//	    for ev := range source {
+//	        if !yield(ev) {
+//	            return
+//	        }
+//	    }
+//	})
+func CopyFromSeq[T any](ctx context.Context, e *Engine, seq iter.Seq[*T]) (int64, error) {
+	defer e.reset()
+	meta, err := e.schema.Introspect(reflect.TypeOf((*T)(nil)))
+	if err != nil {
+		return 0, err
+	}
+
+	next, stop := iter.Pull(seq)
+	defer stop()
+
+	i := 0
+	return e.copyRows(ctx, meta, func() (unsafe.Pointer, error) {
+		entity, ok := next()
+		if !ok {
+			return nil, nil
+		}
+		if entity == nil {
+			return nil, fmt.Errorf("entity %d is nil", i)
+		}
+		i++
+		return unsafe.Pointer(entity), nil
+	})
+}
+
+// copyRows writes the structs returned by next until it returns nil. The
+// column list is fixed by the first row: a key left zero there is generated by
+// the database for every row.
+func (e *Engine) copyRows(ctx context.Context, meta *schema.EntityMeta, next func() (unsafe.Pointer, error)) (int64, error) {
+	first, err := next()
+	if first == nil {
+		return 0, err
+	}
+	if err := e.prepareInsert(meta, first); err != nil {
+		return 0, err
+	}
+
+	autoKey := generatedKey(meta, []unsafe.Pointer{first})
+	fields := insertFields(meta, autoKey)
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("%s has no columns to insert", meta.Name)
+	}
+
+	pending := first
+	nextRow := func() (unsafe.Pointer, error) {
+		if ptr := pending; ptr != nil {
+			pending = nil
+			return ptr, nil
+		}
+		ptr, err := next()
+		if ptr == nil {
+			return nil, err
+		}
+		if err := e.prepareInsert(meta, ptr); err != nil {
+			return nil, err
+		}
+		if autoKey != nil && !autoKey.IsZeroIn(ptr) {
+			return nil, fmt.Errorf("%s is set on a later row but left to the database on the first", autoKey.Name)
+		}
+		return ptr, nil
+	}
+
+	if copier, ok := e.querier(false).(database.Copier); ok {
+		columns := make([]string, len(fields))
+		for i, fm := range fields {
+			columns[i] = fm.DBName
+		}
+
+		st := &Statement{Op: OpCopy, Table: meta.TableName}
+		st.do = func(ctx context.Context, st *Statement) (int64, error) {
+			defer e.markWrite()
+			return copier.CopyFrom(ctx, e.Builder.Schema(), meta.TableName, columns, func() ([]any, error) {
+				ptr, err := nextRow()
+				if ptr == nil {
+					return nil, err
+				}
+				values := make([]any, len(fields))
+				for i, fm := range fields {
+					values[i] = fm.ValueOf(ptr)
+				}
+				return values, nil
+			})
+		}
+		err := e.dispatch(ctx, st)
+		return st.RowsAffected, err
+	}
+
+	// The chunks run on ctx in one transaction, so a failed load writes nothing
+	d := e.derive()
+	d.ctx = ctx
+	chunkSize := max(e.maxParams/len(fields), 1)
+	chunk := make([]unsafe.Pointer, 0, min(chunkSize, 1024))
+	var total int64
+	err = d.atomically(func() error {
+		for {
+			ptr, err := nextRow()
+			if err != nil {
+				return err
+			}
+			if ptr != nil {
+				chunk = append(chunk, ptr)
+			}
+
+			if len(chunk) == chunkSize || (ptr == nil && len(chunk) > 0) {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				if _, err := d.insertRows(meta, fields, nil, chunk, nil); err != nil {
+					return err
+				}
+				total += int64(len(chunk))
+				chunk = chunk[:0]
+			}
+
+			if ptr == nil {
+				return nil
+			}
+		}
+	})
+	if err != nil {
+		if _, ok := d.writer().(database.Beginner); ok && e.tx == nil {
+			total = 0 // Rolled back
+		}
+		return total, err
+	}
+	e.markWrite()
+	return total, nil
+}
@@ -0,0 +1,99 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Konsultn-Engineering/enorm/dialect"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Event struct {
+	ID      uuid.UUID `db:"primary;generator:uuid"`
+	Name    string
+	Payload string
+}
+
+func TestCopyFromUsesCopier(t *testing.T) {
+	conn := newFakeConn(dialect.NewPostgresDialect())
+	copyDB := &fakeCopyDB{}
+	e := New(conn)
+	e.db = copyDB
+
+	events := []*Event{{Name: "a"}, {Name: "b"}}
+	n, err := e.CopyFrom(context.Background(), events)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(2), n)
+	assert.Equal(t, []string{"id", "name", "payload"}, copyDB.columns)
+	assert.Len(t, copyDB.copied, 2)
+	for _, ev := range events {
+		assert.NotEqual(t, uuid.Nil, ev.ID, "generator should fill the key before sending")
+	}
+}
+
+func TestCopyFromFallsBackToChunkedInsert(t *testing.T) {
+	conn := newFakeConn(dialect.NewMySQLDialect())
+	e := New(conn)
+
+	// 3 columns per row: 21845 rows fit into one statement
+	events := make([]Event, 30000)
+	n, err := e.CopyFrom(context.Background(), events)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(30000), n)
+	require.Len(t, conn.db.queries, 4)
+	assert.Equal(t, "BEGIN", conn.db.queries[0])
+	assert.Len(t, conn.db.args[1], 21845*3)
+	assert.Len(t, conn.db.args[2], (30000-21845)*3)
+	assert.Equal(t, "COMMIT", conn.db.queries[3])
+}
+
+type copyKey struct{}
+
+func TestCopyFromFallbackRollsBackOnCancel(t *testing.T) {
+	conn := newFakeConn(dialect.NewMySQLDialect())
+	e := New(conn)
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), copyKey{}, "load"))
+	defer cancel()
+	var seen []any
+	e.Use(func(next Handler) Handler {
+		return func(ctx context.Context, st *Statement) error {
+			seen = append(seen, ctx.Value(copyKey{}))
+			err := next(ctx, st)
+			cancel() // Cancelled while the first chunk is written
+			return err
+		}
+	})
+
+	n, err := e.CopyFrom(ctx, make([]Event, 30000))
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Zero(t, n)
+	assert.Equal(t, []any{"load"}, seen)
+	require.Len(t, conn.db.queries, 3)
+	assert.Equal(t, "ROLLBACK", conn.db.queries[2])
+}
+
+func TestCopyFromSeq(t *testing.T) {
+	conn := newFakeConn(dialect.NewPostgresDialect())
+	copyDB := &fakeCopyDB{}
+	e := New(conn)
+	e.db = copyDB
+
+	seq := func(yield func(*Event) bool) {
+		for i := 0; i < 5; i++ {
+			if !yield(&Event{Name: fmt.Sprint(i)}) {
+				return
+			}
+		}
+	}
+
+	n, err := CopyFromSeq(context.Background(), e, seq)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), n)
+	assert.Equal(t, "4", copyDB.copied[4][1])
+}
@@ -8,11 +8,14 @@ import (
 	"github.com/Konsultn-Engineering/enorm/cache"
 	"github.com/Konsultn-Engineering/enorm/connector"
 	"github.com/Konsultn-Engineering/enorm/database"
+	"github.com/Konsultn-Engineering/enorm/dialect"
 	"github.com/Konsultn-Engineering/enorm/query"
 	"github.com/Konsultn-Engineering/enorm/schema"
+	"github.com/Konsultn-Engineering/enorm/trace"
 	"github.com/Konsultn-Engineering/enorm/visitor"
 	"reflect"
 	"sync"
+	"time"
 	"unsafe"
 )
 
@@ -25,23 +28,71 @@ var scanPtrPool = sync.Pool{
 
 type Engine struct {
 	*query.Builder
+	conn             connector.Connection
 	db               database.Database
+	dialect          dialect.Dialect
 	schema           *schema.Context
+	qcache           cache.QueryCache
+	ctx              context.Context
+	tx               database.Tx // Set while the engine runs in a transaction
+	middleware       []Middleware
+	handler          Handler // middleware chain around execute, nil without middleware
+	tracer           trace.Tracer
 	columnCache      sync.Map
 	scanPool         sync.Pool
 	queryStringCache map[string]string
 	cacheMu          sync.RWMutex
+	preloads         []string
+	maxParams        int
+
+	// Multi-tenancy (see ForTenant)
+	tenant       any
+	tenantSchema func(tenant any) string
+
+	// Per-query scope overrides, cleared by reset
+	unscoped      bool
+	onlyTrashed   bool
+	scopes        []string
+	withoutScopes []string
+	noDefaults    bool
+	usePrimary    bool
+	maxStaleness  time.Duration
+	staleBounded  bool // maxStaleness applies
+}
+
+// Option configures an Engine.
+type Option func(*Engine)
+
+// WithMaxParams sets the bind parameter limit used to split bulk statements
+// (CopyFrom fallback, BulkUpdate) into chunks. Defaults to 65535.
+func WithMaxParams(n int) Option {
+	return func(e *Engine) { e.maxParams = n }
+}
+
+// WithSchema replaces the default schema.Context, e.g. to change the naming
+// strategy or freeze the clock used for auto_now fields.
+func WithSchema(ctx *schema.Context) Option {
+	return func(e *Engine) { e.schema = ctx }
 }
 
-func New(conn connector.Connection) *Engine {
+func New(conn connector.Connection, opts ...Option) *Engine {
 	qc := cache.NewQueryCache()
 	v := visitor.NewSQLVisitor(conn.Dialect(), qc)
 
 	e := &Engine{
 		Builder:          query.NewBuilder("", "", v),
+		conn:             conn,
 		db:               conn.Database(),
+		dialect:          conn.Dialect(),
 		schema:           schema.New(),
+		qcache:           qc,
+		ctx:              context.Background(),
 		queryStringCache: make(map[string]string, 64),
+		maxParams:        maxBindParams,
+	}
+
+	for _, opt := range opts {
+		opt(e)
 	}
 
 	e.scanPool = sync.Pool{
@@ -62,67 +113,135 @@ func New(conn connector.Connection) *Engine {
 	return e
 }
 
+// derive returns an engine sharing e's connection, schema context, query cache
+// and options, with its own builder and no per-query state.
+func (e *Engine) derive() *Engine {
+	d := &Engine{
+		Builder:          query.NewBuilder(e.Builder.Schema(), "", visitor.NewSQLVisitor(e.dialect, e.qcache)),
+		conn:             e.conn,
+		db:               e.db,
+		dialect:          e.dialect,
+		schema:           e.schema,
+		qcache:           e.qcache,
+		ctx:              e.ctx,
+		tx:               e.tx,
+		middleware:       e.middleware,
+		handler:          e.handler,
+		tracer:           e.tracer,
+		queryStringCache: make(map[string]string, 64),
+		maxParams:        e.maxParams,
+		tenant:           e.tenant,
+		tenantSchema:     e.tenantSchema,
+	}
+	d.scanPool.New = e.scanPool.New
+	return d
+}
+
+// reset clears per-query state after a terminal operation: builder
+// conditions, preloads and scope overrides.
+func (e *Engine) reset() {
+	e.Builder.Reset()
+	e.preloads = e.preloads[:0]
+	e.unscoped = false
+	e.onlyTrashed = false
+	e.scopes = e.scopes[:0]
+	e.withoutScopes = e.withoutScopes[:0]
+	e.noDefaults = false
+	e.usePrimary = false
+	e.staleBounded = false
+}
+
 // =============================================================================
 // EXECUTION METHODS
 // =============================================================================
 
 func (e *Engine) FindOne(dest any) (string, error) {
+	defer e.reset()
 	e.Limit(1)
 
 	meta, err := e.schema.Introspect(reflect.TypeOf(dest))
 	if err != nil {
 		return "", err
 	}
+	e.applyScopes(e.Builder, meta, e.scopes)
 
 	query, args, err := e.Builder.Build(meta.TableName, meta.Columns)
 	if err != nil {
 		return "", err
 	}
 
-	rows, err := e.db.Query(query, args...)
-	if err != nil {
-		return query, err
-	}
-	defer rows.Close()
+	query, err = e.query(e.Builder.Statement(), query, args, func(rows database.Rows) (int64, error) {
+		if !rows.Next() {
+			return 0, sql.ErrNoRows
+		}
 
-	if !rows.Next() {
-		return query, sql.ErrNoRows
-	}
+		scanRes := e.scanPool.Get().(*struct {
+			vals []any
+			ptrs []any
+		})
+		defer func() {
+			e.scanPool.Put(scanRes)
+		}()
+
+		// Resize if needed (pointers already set up)
+		colCount := len(meta.Columns)
+		if cap(scanRes.vals) < colCount {
+			// Need to reallocate and rebuild pointers
+			scanRes.vals = make([]any, colCount)
+			scanRes.ptrs = make([]any, colCount)
+			for i := range scanRes.ptrs {
+				scanRes.ptrs[i] = &scanRes.vals[i]
+			}
+		} else {
+			// Just resize, pointers already correct
+			scanRes.vals = scanRes.vals[:colCount]
+			scanRes.ptrs = scanRes.ptrs[:colCount]
+		}
 
-	scanRes := e.scanPool.Get().(*struct {
-		vals []any
-		ptrs []any
+		if err := rows.Scan(scanRes.ptrs...); err != nil {
+			return 0, err
+		}
+		return 1, meta.ScanAndSet(dest, meta.Columns, scanRes.vals)
 	})
-	defer func() {
-		e.scanPool.Put(scanRes)
-	}()
+	if err != nil {
+		return query, err
+	}
 
-	// Resize if needed (pointers already set up)
-	colCount := len(meta.Columns)
-	if cap(scanRes.vals) < colCount {
-		// Need to reallocate and rebuild pointers
-		scanRes.vals = make([]any, colCount)
-		scanRes.ptrs = make([]any, colCount)
-		for i := range scanRes.ptrs {
-			scanRes.ptrs[i] = &scanRes.vals[i]
+	loaded := []reflect.Value{reflect.ValueOf(dest)}
+	if len(e.preloads) > 0 {
+		if err := e.runPreloads(meta, loaded); err != nil {
+			return query, err
 		}
-	} else {
-		// Just resize, pointers already correct
-		scanRes.vals = scanRes.vals[:colCount]
-		scanRes.ptrs = scanRes.ptrs[:colCount]
 	}
+	if err := e.afterFind(meta, loaded); err != nil {
+		return query, err
+	}
+
+	return query, nil
+}
 
-	err = rows.Scan(scanRes.ptrs...)
+// FindByID loads the row whose primary key matches key into dest.
+// key is one value per key column, a single []any tuple, or an entity of the
+// same type carrying the key fields.
+//
+// Example:
+//
+//	e.FindByID(&user, 42)
+//	e.FindByID(&line, orderID, lineNo)
+//	e.FindByID(&line, OrderLine{OrderID: orderID, LineNo: lineNo})
+func (e *Engine) FindByID(dest any, key ...any) (string, error) {
+	meta, err := e.schema.Introspect(reflect.TypeOf(dest))
 	if err != nil {
-		return query, err
+		return "", err
 	}
 
-	err = meta.ScanAndSet(dest, meta.Columns, scanRes.vals)
+	values, err := meta.ResolveKey(key...)
 	if err != nil {
-		return query, err
+		return "", err
 	}
 
-	return query, nil
+	e.Builder.WhereExpr(keyCondition(meta, values))
+	return e.FindOne(dest)
 }
 
 // FindMany optimized for batch operations
@@ -135,6 +254,7 @@ func (e *Engine) FindAll(dest any) (string, error) {
 }
 
 func (e *Engine) Find(dest any) (string, error) {
+	defer e.reset()
 	destVal := reflect.ValueOf(dest)
 	if destVal.Kind() != reflect.Ptr || destVal.Elem().Kind() != reflect.Slice {
 		return "", fmt.Errorf("dest must be pointer to a slice")
@@ -145,54 +265,54 @@ func (e *Engine) Find(dest any) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	e.applyScopes(e.Builder, meta, e.scopes)
 
 	queryStr, args, err := e.Builder.Build(meta.TableName, meta.Columns)
 	if err != nil {
 		return "", err
 	}
 
-	rows, err := e.db.Query(queryStr, args...)
-	if err != nil {
-		return queryStr, err
-	}
-	defer rows.Close()
-
 	colCount := len(meta.Columns)
 	results := make([]any, 0, 100)
 	destPtrs := reflect.MakeSlice(reflect.SliceOf(destType.Elem()), 100, 100)
 
-	ptrs := scanPtrPool.Get().([]any)
-	ptrs = ptrs[:colCount]
-	defer func() {
-		for i := range ptrs {
-			ptrs[i] = nil
-		}
-		scanPtrPool.Put(ptrs[:0])
-	}()
-
-	for i := 0; i < destPtrs.Cap() && rows.Next(); i++ {
-		destPtr := destPtrs.Index(i).Addr()
-		structElem := destPtr.Elem()
-
-		// NUCLEAR: Use unsafe pointer arithmetic instead of FieldByIndex
-		structPtr := unsafe.Pointer(structElem.UnsafeAddr())
-
-		for j, col := range meta.Columns {
-			fieldMeta := meta.ColumnMap[col]
-			if fieldMeta != nil {
-				ptrs[j] = fieldMeta.PointerMaker(structPtr) // FAST!
-			} else {
-				var dummy interface{}
-				ptrs[j] = &dummy // Handle unmapped columns
+	queryStr, err = e.query(e.Builder.Statement(), queryStr, args, func(rows database.Rows) (int64, error) {
+		ptrs := scanPtrPool.Get().([]any)
+		ptrs = ptrs[:colCount]
+		defer func() {
+			for i := range ptrs {
+				ptrs[i] = nil
+			}
+			scanPtrPool.Put(ptrs[:0])
+		}()
+
+		for i := 0; i < destPtrs.Cap() && rows.Next(); i++ {
+			destPtr := destPtrs.Index(i).Addr()
+			structElem := destPtr.Elem()
+
+			// NUCLEAR: Use unsafe pointer arithmetic instead of FieldByIndex
+			structPtr := unsafe.Pointer(structElem.UnsafeAddr())
+
+			for j, col := range meta.Columns {
+				fieldMeta := meta.ColumnMap[col]
+				if fieldMeta != nil {
+					ptrs[j] = fieldMeta.PointerMaker(structPtr) // FAST!
+				} else {
+					var dummy interface{}
+					ptrs[j] = &dummy // Handle unmapped columns
+				}
 			}
-		}
 
-		err = rows.Scan(ptrs...)
-		if err != nil {
-			return queryStr, err
-		}
+			if err := rows.Scan(ptrs...); err != nil {
+				return int64(len(results)), err
+			}
 
-		results = append(results, destPtr.Interface())
+			results = append(results, destPtr.Interface())
+		}
+		return int64(len(results)), nil
+	})
+	if err != nil {
+		return queryStr, err
 	}
 
 	sliceType := destVal.Elem().Type()
@@ -202,6 +322,21 @@ func (e *Engine) Find(dest any) (string, error) {
 	}
 	destVal.Elem().Set(typedSlice)
 
+	if len(e.preloads) > 0 || meta.HasHook(schema.HookAfterFind) {
+		loaded := make([]reflect.Value, len(results))
+		for i, v := range results {
+			loaded[i] = reflect.ValueOf(v)
+		}
+		if len(e.preloads) > 0 {
+			if err := e.runPreloads(meta, loaded); err != nil {
+				return queryStr, err
+			}
+		}
+		if err := e.afterFind(meta, loaded); err != nil {
+			return queryStr, err
+		}
+	}
+
 	return queryStr, nil
 }
 
@@ -373,7 +508,7 @@ func (e *Engine) OrWhereNotBetween(column string, start, end any) *Engine {
 
 func (e *Engine) WhereExists(subqueryFn func(*Engine)) *Engine {
 	e.Builder.WhereExists(func(b *query.Builder) {
-		subEngine := &Engine{Builder: b, db: e.db, schema: e.schema}
+		subEngine := &Engine{Builder: b, db: e.db, dialect: e.dialect, schema: e.schema}
 		subqueryFn(subEngine)
 	})
 	return e
@@ -381,7 +516,7 @@ func (e *Engine) WhereExists(subqueryFn func(*Engine)) *Engine {
 
 func (e *Engine) WhereSubquery(column string, operator string, subqueryFn func(*Engine)) *Engine {
 	e.Builder.WhereSubquery(column, operator, func(b *query.Builder) {
-		subEngine := &Engine{Builder: b, db: e.db, schema: e.schema}
+		subEngine := &Engine{Builder: b, db: e.db, dialect: e.dialect, schema: e.schema}
 		subqueryFn(subEngine)
 	})
 	return e
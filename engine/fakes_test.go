@@ -0,0 +1,218 @@
+package engine
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/Konsultn-Engineering/enorm/connector"
+	"github.com/Konsultn-Engineering/enorm/database"
+	"github.com/Konsultn-Engineering/enorm/dialect"
+	"github.com/Konsultn-Engineering/enorm/trace"
+)
+
+// fakeConn is an in-memory connector.Connection backed by fakeDB.
+type fakeConn struct {
+	db      *fakeDB
+	dialect dialect.Dialect
+}
+
+func newFakeConn(d dialect.Dialect) *fakeConn {
+	return &fakeConn{db: &fakeDB{}, dialect: d}
+}
+
+func (c *fakeConn) DB() *sql.DB                      { return nil }
+func (c *fakeConn) Database() database.Database      { return c.db }
+func (c *fakeConn) Dialect() dialect.Dialect         { return c.dialect }
+func (c *fakeConn) Health(ctx context.Context) error { return nil }
+func (c *fakeConn) Stats() connector.ConnectionStats { return connector.ConnectionStats{} }
+func (c *fakeConn) Close() error                     { return nil }
+
+// fakeCluster routes reads to its replica, unless it lags more than the
+// context allows, and writes to its primary.
+type fakeCluster struct {
+	*fakeConn // Primary
+	replica   *fakeConn
+	lag       time.Duration
+}
+
+func (c *fakeCluster) Primary() connector.Connection              { return c.fakeConn }
+func (c *fakeCluster) Replicas() []connector.Connection           { return []connector.Connection{c.replica} }
+func (c *fakeCluster) Write(context.Context) connector.Connection { return c.fakeConn }
+
+func (c *fakeCluster) Read(ctx context.Context) connector.Connection {
+	if d, ok := connector.MaxStalenessFromContext(ctx); ok && c.lag > d {
+		return c.fakeConn
+	}
+	return c.replica
+}
+
+// fakeDB records executed statements and returns canned results.
+type fakeDB struct {
+	queries []string
+	args    [][]any
+	rows    [][]any   // rows returned by the next Query
+	pending [][][]any // rows returned by the Queries after that, in order
+}
+
+func (d *fakeDB) record(query string, args []any) {
+	d.queries = append(d.queries, query)
+	d.args = append(d.args, args)
+}
+
+func (d *fakeDB) Query(query string, args ...any) (database.Rows, error) {
+	return d.QueryContext(context.Background(), query, args...)
+}
+
+func (d *fakeDB) QueryContext(ctx context.Context, query string, args ...any) (database.Rows, error) {
+	d.record(query, args)
+	rows := &fakeRows{rows: d.rows, pos: -1}
+	d.rows = nil
+	if len(d.pending) > 0 {
+		d.rows, d.pending = d.pending[0], d.pending[1:]
+	}
+	return rows, nil
+}
+
+func (d *fakeDB) Exec(query string, args ...any) (database.Result, error) {
+	return d.ExecContext(context.Background(), query, args...)
+}
+
+func (d *fakeDB) ExecContext(ctx context.Context, query string, args ...any) (database.Result, error) {
+	d.record(query, args)
+	return fakeResult{affected: 1}, nil
+}
+
+// BeginTx records BEGIN; statements of the transaction are recorded on d too.
+func (d *fakeDB) BeginTx(ctx context.Context) (database.Tx, error) {
+	d.record("BEGIN", nil)
+	return &fakeTx{d}, nil
+}
+
+func (d *fakeDB) PingContext(ctx context.Context) error   { return nil }
+func (d *fakeDB) Close() error                            { return nil }
+func (d *fakeDB) SetMaxOpenConns(n int)                   {}
+func (d *fakeDB) SetMaxIdleConns(n int)                   {}
+func (d *fakeDB) Prepare(query string) (*sql.Stmt, error) { return nil, fmt.Errorf("not supported") }
+
+type fakeTx struct {
+	*fakeDB
+}
+
+func (t *fakeTx) Commit() error   { t.record("COMMIT", nil); return nil }
+func (t *fakeTx) Rollback() error { t.record("ROLLBACK", nil); return nil }
+
+// fakeCopyDB adds COPY support to fakeDB.
+type fakeCopyDB struct {
+	fakeDB
+	copied  [][]any
+	columns []string
+}
+
+func (d *fakeCopyDB) CopyFrom(ctx context.Context, schema, table string, columns []string, next func() ([]any, error)) (int64, error) {
+	d.columns = columns
+	for {
+		row, err := next()
+		if err != nil {
+			return 0, err
+		}
+		if row == nil {
+			return int64(len(d.copied)), nil
+		}
+		d.copied = append(d.copied, row)
+	}
+}
+
+type fakeRows struct {
+	rows [][]any
+	pos  int
+}
+
+func (r *fakeRows) Next() bool {
+	r.pos++
+	return r.pos < len(r.rows)
+}
+
+func (r *fakeRows) Scan(dest ...any) error {
+	for i, v := range r.rows[r.pos] {
+		if i >= len(dest) {
+			break
+		}
+		if s, ok := dest[i].(sql.Scanner); ok {
+			if err := s.Scan(v); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := assignScan(dest[i], v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *fakeRows) Close() error               { return nil }
+func (r *fakeRows) Columns() ([]string, error) { return nil, nil }
+func (r *fakeRows) Values() ([]any, error)     { return r.rows[r.pos], nil }
+
+// assignScan stores v in dest for the handful of types the tests use.
+func assignScan(dest, v any) error {
+	if v == nil {
+		reflect.ValueOf(dest).Elem().SetZero()
+		return nil
+	}
+	switch d := dest.(type) {
+	case *uint64:
+		*d = uint64(v.(int64))
+	case *int64:
+		*d = v.(int64)
+	case *string:
+		*d = v.(string)
+	case *any:
+		*d = v
+	default:
+		return fmt.Errorf("fakeRows: unsupported destination %T", dest)
+	}
+	return nil
+}
+
+type fakeResult struct {
+	affected int64
+}
+
+func (r fakeResult) LastInsertId() (int64, error) { return 0, nil }
+func (r fakeResult) RowsAffected() (int64, error) { return r.affected, nil }
+
+// fakeTracer records spans; a span's parent is the span in its start context.
+type fakeTracer struct {
+	spans []*fakeSpan
+}
+
+type fakeSpan struct {
+	name   string
+	attrs  map[string]any
+	parent *fakeSpan
+	err    error
+	ended  bool
+}
+
+type spanKey struct{}
+
+func (f *fakeTracer) Start(ctx context.Context, name string, attrs ...trace.Attribute) (context.Context, trace.Span) {
+	parent, _ := ctx.Value(spanKey{}).(*fakeSpan)
+	s := &fakeSpan{name: name, attrs: map[string]any{}, parent: parent}
+	s.SetAttributes(attrs...)
+	f.spans = append(f.spans, s)
+	return context.WithValue(ctx, spanKey{}, s), s
+}
+
+func (s *fakeSpan) SetAttributes(attrs ...trace.Attribute) {
+	for _, a := range attrs {
+		s.attrs[a.Key] = a.Value
+	}
+}
+
+func (s *fakeSpan) RecordError(err error) { s.err = err }
+func (s *fakeSpan) End()                  { s.ended = true }
@@ -0,0 +1,115 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"unsafe"
+
+	"github.com/Konsultn-Engineering/enorm/dialect"
+	"github.com/Konsultn-Engineering/enorm/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Order struct {
+	ID     int64
+	Status string
+	Label  string `db:"-"`
+	fail   bool
+}
+
+type AuditEntry struct {
+	ID      int64
+	OrderID int64
+}
+
+func (o *Order) BeforeCreate(ctx context.Context) error {
+	if o.Status == "" {
+		o.Status = "new"
+	}
+	return nil
+}
+
+func (o *Order) AfterCreate(ctx context.Context) error {
+	if o.fail {
+		return errors.New("boom")
+	}
+	_, err := FromContext(ctx).Insert(&AuditEntry{OrderID: o.ID})
+	return err
+}
+
+func (o *Order) BeforeDelete(ctx context.Context) error {
+	if o.Status == "paid" {
+		return errors.New("paid orders cannot be deleted")
+	}
+	return nil
+}
+
+func (o *Order) AfterFind(ctx context.Context) error {
+	o.Label = fmt.Sprintf("#%d", o.ID)
+	return nil
+}
+
+func TestLifecycleHooks(t *testing.T) {
+	conn := newFakeConn(dialect.NewPostgresDialect())
+	e := New(conn)
+
+	order := Order{ID: 1}
+	conn.db.rows = [][]any{{int64(7)}} // the audit entry's key
+	_, err := e.Insert(&order)
+	require.NoError(t, err)
+	assert.Equal(t, "new", order.Status)
+	require.Len(t, conn.db.queries, 4)
+	assert.Equal(t, "BEGIN", conn.db.queries[0])
+	assert.Contains(t, conn.db.queries[1], `INSERT INTO "orders"`)
+	assert.Contains(t, conn.db.queries[2], `INSERT INTO "audit_entries"`)
+	assert.Equal(t, "COMMIT", conn.db.queries[3])
+
+	// An After hook error rolls the write back
+	conn.db.queries = nil
+	_, err = e.Insert(&Order{ID: 2, fail: true})
+	assert.ErrorContains(t, err, "Order.AfterCreate: boom")
+	assert.Equal(t, "ROLLBACK", conn.db.queries[len(conn.db.queries)-1])
+
+	// A Before hook error aborts before anything is written
+	conn.db.queries = nil
+	_, err = e.Delete(&Order{ID: 3, Status: "paid"})
+	assert.ErrorContains(t, err, "paid orders cannot be deleted")
+	assert.Equal(t, []string{"BEGIN", "ROLLBACK"}, conn.db.queries)
+
+	conn.db.rows = [][]any{{int64(5), "new"}, {int64(6), "paid"}}
+	var orders []*Order
+	_, err = e.Find(&orders)
+	require.NoError(t, err)
+	require.Len(t, orders, 2)
+	assert.Equal(t, "#5", orders[0].Label)
+	assert.Equal(t, "#6", orders[1].Label)
+}
+
+type Visit struct {
+	ID    int64
+	found int
+}
+
+func (v *Visit) AfterFind(ctx context.Context) error {
+	v.found++
+	return nil
+}
+
+func TestRunHookCallsResolvedMethod(t *testing.T) {
+	meta, err := schema.New().Introspect(reflect.TypeOf(Visit{}))
+	require.NoError(t, err)
+
+	var visit Visit
+	ptr := unsafe.Pointer(&visit)
+	ctx := context.Background()
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = meta.RunHook(ctx, schema.HookAfterFind, ptr)
+	})
+	assert.Zero(t, allocs)
+	assert.Equal(t, 101, visit.found)
+	assert.NoError(t, meta.RunHook(ctx, schema.HookAfterCreate, ptr))
+}
@@ -0,0 +1,56 @@
+package engine
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/Konsultn-Engineering/enorm/dialect"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Ticket struct {
+	ID       string         `db:"primary;generator:uuid"`
+	Code     [16]byte       `db:"generator:ulid"`
+	Sequence uint64         `db:"generator:snowflake"`
+	Slug     *string        `db:"generator:nanoid"`
+	Ref      sql.NullString `db:"generator:uuid"`
+}
+
+func TestInsertAppliesGenerators(t *testing.T) {
+	conn := newFakeConn(dialect.NewPostgresDialect())
+	e := New(conn)
+
+	var ticket Ticket
+	_, err := e.Insert(&ticket)
+	require.NoError(t, err)
+
+	_, err = uuid.Parse(ticket.ID)
+	assert.NoError(t, err, "uuid should be stored in its canonical text form")
+	assert.NotEqual(t, [16]byte{}, ticket.Code)
+	assert.NotZero(t, ticket.Sequence)
+	require.NotNil(t, ticket.Slug)
+	assert.Len(t, *ticket.Slug, 21)
+	assert.True(t, ticket.Ref.Valid)
+
+	// Values already set are left alone
+	ticket2 := Ticket{ID: "fixed"}
+	_, err = e.Insert(&ticket2)
+	require.NoError(t, err)
+	assert.Equal(t, "fixed", ticket2.ID)
+}
+
+type BadTicket struct {
+	ID   uint64
+	Code float64 `db:"generator:uuid"`
+}
+
+func TestInsertRejectsUnassignableGenerator(t *testing.T) {
+	e := New(newFakeConn(dialect.NewPostgresDialect()))
+
+	_, err := e.Insert(&BadTicket{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "field Code")
+	assert.Contains(t, err.Error(), "float64")
+}
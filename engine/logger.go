@@ -0,0 +1,148 @@
+package engine
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// redacted replaces logged arguments bound to sensitive columns.
+const redacted = "[REDACTED]"
+
+const modulePrefix = "github.com/Konsultn-Engineering/enorm/"
+
+// LogConfig controls which statements WithLogger records.
+type LogConfig struct {
+	// Level of statements that neither fail nor exceed SlowThreshold.
+	Level slog.Level
+
+	// Statements taking at least SlowThreshold are logged at slog.LevelWarn
+	// and never sampled. Zero disables slow-query logging.
+	SlowThreshold time.Duration
+
+	// Log only one in SampleEvery of the statements logged at Level. Errors
+	// and slow statements are always logged. Values below 2 log every
+	// statement.
+	SampleEvery int
+}
+
+// WithLogger logs every statement the engine runs to l with its SQL, args,
+// duration, row count and the caller location outside enorm. Failed
+// statements are logged at slog.LevelError and slow ones at slog.LevelWarn.
+// Args bound to columns tagged `sensitive` are logged as "[REDACTED]".
+//
+// Example:
+//
+//	type User struct {
+//	    ID       int64
+//	    Password string `db:"password;sensitive"`
+//	}
+//
+//	e := engine.New(conn, engine.WithLogger(slog.Default(), engine.LogConfig{
+//	    Level:         slog.LevelDebug,
+//	    SlowThreshold: 200 * time.Millisecond,
+//	    SampleEvery:   10,
+//	}))
+func WithLogger(l *slog.Logger, cfg LogConfig) Option {
+	return func(e *Engine) { e.Use(e.logMiddleware(l, cfg)) }
+}
+
+func (e *Engine) logMiddleware(l *slog.Logger, cfg LogConfig) Middleware {
+	var seen atomic.Uint64
+	return func(next Handler) Handler {
+		return func(ctx context.Context, st *Statement) error {
+			err := next(ctx, st)
+
+			level, msg := cfg.Level, "query"
+			switch {
+			case err != nil:
+				level, msg = slog.LevelError, "query failed"
+			case cfg.SlowThreshold > 0 && st.Duration >= cfg.SlowThreshold:
+				level, msg = slog.LevelWarn, "slow query"
+			case cfg.SampleEvery > 1 && (seen.Add(1)-1)%uint64(cfg.SampleEvery) != 0:
+				return err
+			}
+			if !l.Enabled(ctx, level) {
+				return err
+			}
+
+			attrs := []slog.Attr{
+				slog.String("op", string(st.Op)),
+				slog.String("table", st.Table),
+				slog.String("sql", st.SQL),
+				slog.Any("args", e.redactArgs(st)),
+				slog.Duration("duration", st.Duration),
+				slog.Int64("rows", st.RowsAffected),
+			}
+			if caller := callerLocation(); caller != "" {
+				attrs = append(attrs, slog.String("caller", caller))
+			}
+			if err != nil {
+				attrs = append(attrs, slog.Any("error", err))
+			}
+			l.LogAttrs(ctx, level, msg, attrs...)
+			return err
+		}
+	}
+}
+
+// redactArgs returns st.Args with values bound to sensitive columns replaced.
+// When arguments cannot be attributed to columns, all of them are redacted if
+// the table has any sensitive column.
+func (e *Engine) redactArgs(st *Statement) []any {
+	if len(st.Args) == 0 || !e.schema.HasSensitive(st.Table) {
+		return st.Args
+	}
+
+	cols := st.ArgColumns()
+	args := make([]any, len(st.Args))
+	for i, arg := range st.Args {
+		args[i] = arg
+		if len(cols) != len(args) {
+			args[i] = redacted
+			continue
+		}
+		table, column := st.Table, cols[i]
+		if dot := strings.LastIndexByte(column, '.'); dot >= 0 {
+			table, column = column[:dot], column[dot+1:]
+		}
+		if e.schema.IsSensitive(table, column) {
+			args[i] = redacted
+		}
+	}
+	return args
+}
+
+// callerLocation returns file:line of the first frame outside enorm below the
+// engine operation that dispatched the current statement.
+func callerLocation() string {
+	pcs := make([]uintptr, 64)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+
+	dispatched := false
+	for {
+		frame, more := frames.Next()
+		switch {
+		case strings.HasSuffix(frame.Function, ".(*Engine).dispatch"):
+			dispatched = true
+		case dispatched && isCaller(frame):
+			return frame.File + ":" + strconv.Itoa(frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// isCaller reports whether frame belongs to code using enorm rather than to
+// enorm itself or the runtime. enorm's own tests count as callers.
+func isCaller(frame runtime.Frame) bool {
+	if strings.HasSuffix(frame.File, "_test.go") {
+		return true
+	}
+	return !strings.HasPrefix(frame.Function, modulePrefix) && !strings.HasPrefix(frame.Function, "runtime.")
+}
@@ -0,0 +1,119 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Konsultn-Engineering/enorm/dialect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Login struct {
+	ID       int64
+	Email    string
+	Password string `db:"password;sensitive"`
+}
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	records := func() []map[string]any {
+		var out []map[string]any
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			var rec map[string]any
+			require.NoError(t, json.Unmarshal([]byte(line), &rec))
+			out = append(out, rec)
+		}
+		buf.Reset()
+		return out
+	}
+
+	conn := newFakeConn(dialect.NewPostgresDialect())
+	e := New(conn, WithLogger(logger, LogConfig{Level: slog.LevelDebug}))
+
+	_, err := e.Insert(&Login{ID: 1, Email: "a@example.com", Password: "hunter2"})
+	require.NoError(t, err)
+	_, err = e.WhereEq("password", "hunter2").Find(&[]*Login{})
+	require.NoError(t, err)
+
+	recs := records()
+	require.Len(t, recs, 2)
+	assert.Equal(t, "DEBUG", recs[0]["level"])
+	assert.Equal(t, "INSERT", recs[0]["op"])
+	assert.Equal(t, "logins", recs[0]["table"])
+	assert.Equal(t, []any{float64(1), "a@example.com", "[REDACTED]"}, recs[0]["args"])
+	assert.Equal(t, float64(1), recs[0]["rows"])
+	assert.Contains(t, recs[0]["caller"], "logger_test.go:")
+	assert.Equal(t, []any{"[REDACTED]"}, recs[1]["args"])
+	assert.NotContains(t, fmt.Sprint(recs), "hunter2")
+
+	// Slow and failed statements bypass sampling
+	conn = newFakeConn(dialect.NewPostgresDialect())
+	e = New(conn, WithLogger(logger, LogConfig{Level: slog.LevelDebug, SlowThreshold: time.Nanosecond, SampleEvery: 100}))
+	e.Use(func(next Handler) Handler {
+		return func(ctx context.Context, st *Statement) error {
+			if st.Op == OpDelete {
+				return errors.New("boom")
+			}
+			return next(ctx, st)
+		}
+	})
+	_, err = e.Update(&Login{ID: 1, Email: "b@example.com"})
+	require.NoError(t, err)
+	_, err = e.ForceDelete(&Login{ID: 1})
+	require.Error(t, err)
+
+	recs = records()
+	require.Len(t, recs, 2)
+	assert.Equal(t, "WARN", recs[0]["level"])
+	assert.Equal(t, "slow query", recs[0]["msg"])
+	assert.Equal(t, []any{"b@example.com", "[REDACTED]", float64(1)}, recs[0]["args"])
+	assert.Equal(t, "ERROR", recs[1]["level"])
+	assert.Equal(t, "boom", recs[1]["error"])
+
+	// Only one in SampleEvery ordinary statements is logged
+	e = New(conn, WithLogger(logger, LogConfig{Level: slog.LevelDebug, SampleEvery: 3}))
+	for range 5 {
+		_, err = e.Update(&Login{ID: 1})
+		require.NoError(t, err)
+	}
+	assert.Len(t, records(), 2)
+}
+
+func TestLoggerLeavesQueryCacheStatsAlone(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	run := func(e *Engine) {
+		for i := range 3 {
+			_, err := e.Insert(&Login{ID: int64(i + 1), Password: "hunter2"})
+			require.NoError(t, err)
+		}
+		for range 2 {
+			_, err := e.WhereEq("password", "hunter2").Find(&[]*Login{})
+			require.NoError(t, err)
+		}
+	}
+
+	plain := New(newFakeConn(dialect.NewPostgresDialect()))
+	run(plain)
+	logged := New(newFakeConn(dialect.NewPostgresDialect()), WithLogger(logger, LogConfig{Level: slog.LevelDebug}))
+	run(logged)
+
+	stats := logged.qcache.Stats()
+	assert.Equal(t, plain.qcache.Stats(), stats)
+	assert.Equal(t, uint64(1), stats.Hits)
+	assert.Equal(t, uint64(1), stats.Misses)
+	// Arguments are still attributed to their columns
+	assert.Contains(t, buf.String(), `"args":[3,"","[REDACTED]"]`)
+	assert.Equal(t, 2, strings.Count(buf.String(), `"args":["[REDACTED]"]`))
+	assert.NotContains(t, buf.String(), "hunter2")
+}
@@ -0,0 +1,31 @@
+package engine
+
+import (
+	"context"
+
+	"github.com/Konsultn-Engineering/enorm/metrics"
+)
+
+// WithMetrics reports the engine to c: the latency and errors of every
+// statement by operation, and the query cache and connection pool statistics
+// labelled with name. Engines sharing a collector need distinct names; their
+// statement metrics are combined.
+//
+// Example:
+//
+//	c := metrics.NewCollector()
+//	e := engine.New(conn, engine.WithMetrics(c, "main"))
+//	http.Handle("/metrics", c)
+func WithMetrics(c *metrics.Collector, name string) Option {
+	return func(e *Engine) {
+		c.AddQueryCache(name, e.qcache)
+		c.AddConnection(name, e.conn)
+		e.Use(func(next Handler) Handler {
+			return func(ctx context.Context, st *Statement) error {
+				err := next(ctx, st)
+				c.ObserveStatement(string(st.Op), st.Duration, err)
+				return err
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package engine
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Konsultn-Engineering/enorm/dialect"
+	"github.com/Konsultn-Engineering/enorm/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+	conn := newFakeConn(dialect.NewPostgresDialect())
+	c := metrics.NewCollector(0.5, 1)
+	e := New(conn, WithMetrics(c, "main"))
+
+	for range 2 {
+		_, err := e.WhereEq("title", "a").Find(&[]*Note{})
+		require.NoError(t, err)
+	}
+	_, err := e.Update(&Note{ID: 1, Title: "a"})
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	c.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rec.Body.String()
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", rec.Header().Get("Content-Type"))
+	for _, line := range []string{
+		"# TYPE enorm_statement_duration_seconds histogram",
+		`enorm_statement_duration_seconds_bucket{op="SELECT",le="0.5"} 2`,
+		`enorm_statement_duration_seconds_bucket{op="SELECT",le="+Inf"} 2`,
+		`enorm_statement_duration_seconds_count{op="UPDATE"} 1`,
+		`enorm_statement_errors_total{op="SELECT"} 0`,
+		`enorm_query_cache_hits_total{cache="main"} 1`,
+		`enorm_query_cache_misses_total{cache="main"} 1`,
+		`enorm_pool_open_connections{pool="main",role="primary"} 0`,
+	} {
+		assert.Contains(t, body, line+"\n")
+	}
+}
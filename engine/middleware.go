@@ -0,0 +1,115 @@
+package engine
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/Konsultn-Engineering/enorm/ast"
+)
+
+// Operation is the kind of statement passed to middleware.
+type Operation string
+
+const (
+	OpSelect Operation = "SELECT"
+	OpInsert Operation = "INSERT"
+	OpUpdate Operation = "UPDATE"
+	OpDelete Operation = "DELETE"
+	OpCopy   Operation = "COPY" // Bulk load through the COPY protocol; SQL and Node are empty
+)
+
+// Statement is a single database round trip as seen by middleware. Op, Table,
+// Node, SQL and Args describe it before it runs; Duration and RowsAffected are
+// filled in once the next handler returns.
+type Statement struct {
+	Op    Operation
+	Table string   // Target table, unqualified
+	Node  ast.Node // Statement the SQL was rendered from; do not modify
+	SQL   string
+	Args  []any // May be shared with the query cache: replace the slice instead of modifying it
+
+	Duration     time.Duration // Time spent executing, including reading the rows of a query
+	RowsAffected int64         // Rows written, or rows read for queries
+
+	do      func(ctx context.Context, st *Statement) (int64, error)
+	argCols []string
+}
+
+// ArgColumns returns, parallel to Args, the column each argument is compared
+// with or assigned to: "column" or "table.column", and "" where unknown.
+// Returns nil for COPY statements.
+func (st *Statement) ArgColumns() []string {
+	return st.argCols
+}
+
+// Handler executes a statement. The error is the statement's outcome, as
+// returned to the caller of the engine operation.
+type Handler func(ctx context.Context, st *Statement) error
+
+// Middleware wraps a Handler. It may inspect or rewrite the statement before
+// calling next, observe Duration, RowsAffected and the error afterwards, or
+// return without calling next to reject the statement.
+type Middleware func(next Handler) Handler
+
+// Use appends middleware to the chain every statement of the engine runs
+// through. The first middleware added is the outermost. Engines derived
+// afterwards (ForTenant, WithContext, Transaction) inherit the chain.
+// Use is not safe to call while the engine is executing statements.
+//
+// Example:
+//
+//	e.Use(func(next engine.Handler) engine.Handler {
+//	    return func(ctx context.Context, st *engine.Statement) error {
+//	        err := next(ctx, st)
+//	        log.Printf("%s %s: %d rows in %s (err=%v)", st.Op, st.Table, st.RowsAffected, st.Duration, err)
+//	        return err
+//	    }
+//	})
+func (e *Engine) Use(mw ...Middleware) {
+	e.middleware = append(slices.Clip(e.middleware), mw...)
+
+	h := Handler(execute)
+	for i := len(e.middleware) - 1; i >= 0; i-- {
+		h = e.middleware[i](h)
+	}
+	e.handler = h
+}
+
+// dispatch runs st through the middleware chain.
+func (e *Engine) dispatch(ctx context.Context, st *Statement) error {
+	if e.handler == nil {
+		return execute(ctx, st)
+	}
+	return e.handler(ctx, st)
+}
+
+// execute is the innermost handler: it runs the statement and records the
+// duration and row count.
+func execute(ctx context.Context, st *Statement) error {
+	start := time.Now()
+	n, err := st.do(ctx, st)
+	st.Duration = time.Since(start)
+	st.RowsAffected = n
+	return err
+}
+
+// newStatement describes node for middleware. sql and args must come from the
+// last Build or Render of the engine's visitor, which also mapped the args to columns.
+func (e *Engine) newStatement(node ast.Node, sql string, args []any) *Statement {
+	st := &Statement{Node: node, SQL: sql, Args: args, argCols: e.Builder.Visitor().ArgColumns()}
+	switch n := node.(type) {
+	case *ast.SelectStmt:
+		st.Op = OpSelect
+		if n.From != nil {
+			st.Table = n.From.Name
+		}
+	case *ast.InsertStmt:
+		st.Op, st.Table = OpInsert, n.Table.Name
+	case *ast.UpdateStmt:
+		st.Op, st.Table = OpUpdate, n.Table.Name
+	case *ast.DeleteStmt:
+		st.Op, st.Table = OpDelete, n.Table.Name
+	}
+	return st
+}
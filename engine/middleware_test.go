@@ -0,0 +1,61 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Konsultn-Engineering/enorm/ast"
+	"github.com/Konsultn-Engineering/enorm/dialect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware(t *testing.T) {
+	conn := newFakeConn(dialect.NewPostgresDialect())
+	e := New(conn)
+
+	var seen []Statement
+	e.Use(func(next Handler) Handler {
+		return func(ctx context.Context, st *Statement) error {
+			err := next(ctx, st)
+			seen = append(seen, *st)
+			return err
+		}
+	})
+	e.Use(func(next Handler) Handler {
+		return func(ctx context.Context, st *Statement) error {
+			if st.Op == OpDelete {
+				return errors.New("deletes are disabled")
+			}
+			st.SQL = "/* rewritten */ " + st.SQL
+			return next(ctx, st)
+		}
+	})
+
+	conn.db.rows = [][]any{{int64(1), "a", nil}, {int64(2), "b", nil}}
+	var notes []*Note
+	queryStr, err := e.Find(&notes)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(queryStr, "/* rewritten */ SELECT"))
+	assert.Equal(t, queryStr, conn.db.queries[0])
+
+	_, err = e.Update(&Note{ID: 1, Title: "a"})
+	require.NoError(t, err)
+
+	_, err = e.ForceDelete(&Note{ID: 1})
+	assert.EqualError(t, err, "deletes are disabled")
+	assert.Len(t, conn.db.queries, 2)
+
+	require.Len(t, seen, 3)
+	assert.Equal(t, OpSelect, seen[0].Op)
+	assert.Equal(t, "notes", seen[0].Table)
+	assert.Equal(t, int64(2), seen[0].RowsAffected)
+	assert.IsType(t, &ast.SelectStmt{}, seen[0].Node)
+	assert.Equal(t, OpUpdate, seen[1].Op)
+	assert.Equal(t, int64(1), seen[1].RowsAffected)
+	assert.Equal(t, []any{"a", (*time.Time)(nil), int64(1)}, seen[1].Args)
+	assert.Equal(t, OpDelete, seen[2].Op)
+}
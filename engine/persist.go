@@ -0,0 +1,634 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unsafe"
+
+	"github.com/Konsultn-Engineering/enorm/ast"
+	"github.com/Konsultn-Engineering/enorm/database"
+	"github.com/Konsultn-Engineering/enorm/schema"
+)
+
+// =============================================================================
+// WRITE OPERATIONS
+// =============================================================================
+
+// Insert writes entity as a new row. entity may be a pointer to a struct or a
+// slice ([]*T, []T or a pointer to either) for a single multi-row INSERT.
+//
+// A zero-valued single-column primary key is treated as database-generated:
+// it is omitted from the column list and read back (RETURNING on PostgreSQL,
+// LastInsertId for single-row inserts elsewhere).
+//
+// Entities are validated before anything is sent; failures are returned as
+// schema.ValidationErrors. BeforeCreate and AfterCreate hooks run for every
+// entity (see schema.BeforeCreateHook).
+func (e *Engine) Insert(entity any) (string, error) {
+	defer e.reset()
+	return e.insert(entity, nil)
+}
+
+// Update writes the non-key columns of entity, matching the row by primary key.
+// When columns are given (column or Go field names), only those are written,
+// plus any auto_now columns, which are always set to the current time.
+// BeforeUpdate and AfterUpdate hooks run around the statement.
+//
+// Composite keys render as a row-value comparison:
+//
+//	UPDATE "order_lines" SET "qty" = $1 WHERE ("order_id", "line_no") = ($2, $3)
+func (e *Engine) Update(entity any, columns ...string) (string, error) {
+	defer e.reset()
+	meta, ptr, err := e.entityMeta(entity)
+	if err != nil {
+		return "", err
+	}
+
+	keys, err := meta.KeyValues(ptr)
+	if err != nil {
+		return "", err
+	}
+
+	fields, err := updateFields(meta, columns)
+	if err != nil {
+		return "", err
+	}
+
+	var queryStr string
+	err = e.withHooks(meta, schema.HookBeforeUpdate|schema.HookAfterUpdate, func(ctx context.Context) error {
+		if err := meta.RunHook(ctx, schema.HookBeforeUpdate, ptr); err != nil {
+			return err
+		}
+		if err := e.stampTenant(meta, ptr); err != nil {
+			return err
+		}
+		fields = appendMissing(fields, e.schema.PrepareUpdate(meta, ptr))
+		if err := e.schema.Validate(meta, ptr, fields...); err != nil {
+			return err
+		}
+
+		stmt := ast.NewUpdateStmt(e.table(meta))
+		defer stmt.Release()
+
+		for _, fm := range fields {
+			stmt.AddSet(fm.DBName, ast.NewValue(fm.ValueOf(ptr)))
+		}
+		stmt.AddWhereCondition(keyCondition(meta, keys), ast.OpAnd)
+		if err := e.scopeTenant(stmt, meta); err != nil {
+			return err
+		}
+
+		var err error
+		if queryStr, _, err = e.exec(stmt); err != nil {
+			return err
+		}
+		return meta.RunHook(ctx, schema.HookAfterUpdate, ptr)
+	})
+	return queryStr, err
+}
+
+// UpdateWhere writes updates (column or Go field name -> value) to every row of
+// model's table matching the conditions added with Where and friends, then
+// clears those conditions. Soft-deleted rows are skipped unless Unscoped.
+// auto_now columns missing from updates are added to the SET list with the
+// current time.
+//
+// Example:
+//
+//	e.WhereEq("status", "draft").UpdateWhere(&Post{}, map[string]any{"status": "archived"})
+func (e *Engine) UpdateWhere(model any, updates map[string]any) (string, error) {
+	defer e.reset()
+
+	if len(updates) == 0 {
+		return "", fmt.Errorf("no columns to update")
+	}
+
+	meta, err := e.schema.Introspect(reflect.TypeOf(model))
+	if err != nil {
+		return "", err
+	}
+
+	// Sorted so that equal updates render identical SQL
+	names := make([]string, 0, len(updates))
+	for name := range updates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	stmt := ast.NewUpdateStmt(e.table(meta))
+	defer func() {
+		// The WHERE clause belongs to the builder and is released by Reset
+		stmt.Where = nil
+		stmt.Release()
+	}()
+
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		fm := lookupField(meta, name)
+		if fm == nil {
+			return "", fmt.Errorf("%s has no column %s", meta.Name, name)
+		}
+		if fm == meta.Tenant {
+			return "", fmt.Errorf("cannot update tenant column %s", fm.DBName)
+		}
+		if err := e.schema.ValidateValue(fm, updates[name]); err != nil {
+			return "", err
+		}
+		stmt.AddSet(fm.DBName, ast.NewValue(updates[name]))
+		set[fm.DBName] = true
+	}
+
+	var now time.Time
+	for _, fm := range meta.AutoNowFields() {
+		if set[fm.DBName] {
+			continue
+		}
+		if now.IsZero() {
+			now = e.schema.Now()
+		}
+		stmt.AddSet(fm.DBName, ast.NewValue(fm.TimestampValue(now)))
+	}
+
+	e.applyScopes(e.Builder, meta, e.scopes)
+	if err := e.Builder.GetFirstError(); err != nil {
+		return "", err
+	}
+	stmt.Where = e.Builder.WhereClause()
+
+	queryStr, _, err := e.exec(stmt)
+	return queryStr, err
+}
+
+// Delete removes the row matching entity's primary key. Entities with a soft
+// delete field are not removed: the field is set to the current time instead
+// (see Unscoped and ForceDelete). BeforeDelete and AfterDelete hooks run in
+// both cases.
+func (e *Engine) Delete(entity any) (string, error) {
+	defer e.reset()
+
+	meta, ptr, err := e.entityMeta(entity)
+	if err != nil {
+		return "", err
+	}
+
+	keys, err := meta.KeyValues(ptr)
+	if err != nil {
+		return "", err
+	}
+
+	var queryStr string
+	err = e.withHooks(meta, schema.HookBeforeDelete|schema.HookAfterDelete, func(ctx context.Context) error {
+		if err := meta.RunHook(ctx, schema.HookBeforeDelete, ptr); err != nil {
+			return err
+		}
+
+		var err error
+		if meta.SoftDelete != nil && !e.unscoped {
+			queryStr, err = e.softDelete(meta, ptr, keys)
+		} else {
+			queryStr, err = e.hardDelete(meta, keys)
+		}
+		if err != nil {
+			return err
+		}
+		return meta.RunHook(ctx, schema.HookAfterDelete, ptr)
+	})
+	return queryStr, err
+}
+
+// hardDelete removes the row matching keys.
+func (e *Engine) hardDelete(meta *schema.EntityMeta, keys []any) (string, error) {
+	stmt := ast.NewDeleteStmt(e.table(meta))
+	defer stmt.Release()
+	stmt.AddWhereCondition(keyCondition(meta, keys), ast.OpAnd)
+	if err := e.scopeTenant(stmt, meta); err != nil {
+		return "", err
+	}
+
+	queryStr, _, err := e.exec(stmt)
+	return queryStr, err
+}
+
+// =============================================================================
+// HELPERS
+// =============================================================================
+
+// insert renders and executes a (multi-row) INSERT with an optional conflict clause.
+func (e *Engine) insert(entities any, conflict *OnConflict) (string, error) {
+	meta, rows, err := e.entityBatch(entities)
+	if err != nil {
+		return "", err
+	}
+
+	var queryStr string
+	err = e.withHooks(meta, schema.HookBeforeCreate|schema.HookAfterCreate, func(ctx context.Context) error {
+		for _, ptr := range rows {
+			if err := meta.RunHook(ctx, schema.HookBeforeCreate, ptr); err != nil {
+				return err
+			}
+			if err := e.prepareInsert(meta, ptr); err != nil {
+				return err
+			}
+		}
+
+		autoKey := generatedKey(meta, rows)
+		var err error
+		if queryStr, err = e.insertRows(meta, insertFields(meta, autoKey), autoKey, rows, conflict); err != nil {
+			return err
+		}
+
+		for _, ptr := range rows {
+			if err := meta.RunHook(ctx, schema.HookAfterCreate, ptr); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return queryStr, err
+}
+
+// prepareInsert fills the tenant, generated IDs and timestamps on a new row
+// and validates it.
+func (e *Engine) prepareInsert(meta *schema.EntityMeta, ptr unsafe.Pointer) error {
+	if err := e.stampTenant(meta, ptr); err != nil {
+		return err
+	}
+	if err := e.schema.PrepareInsert(meta, ptr); err != nil {
+		return err
+	}
+	return e.schema.Validate(meta, ptr)
+}
+
+// insertRows renders and executes an INSERT of fields for rows. A non-nil
+// autoKey is read back into rows after the insert.
+func (e *Engine) insertRows(meta *schema.EntityMeta, fields []*schema.FieldMeta, autoKey *schema.FieldMeta,
+	rows []unsafe.Pointer, conflict *OnConflict) (string, error) {
+	columns := make([]string, len(fields))
+	for i, fm := range fields {
+		columns[i] = fm.DBName
+	}
+
+	stmt := ast.NewInsertStmt(e.table(meta), columns...)
+	defer stmt.Release()
+
+	for _, ptr := range rows {
+		values := make([]ast.Node, len(fields))
+		for i, fm := range fields {
+			values[i] = ast.NewValue(fm.ValueOf(ptr))
+		}
+		stmt.AddRow(values...)
+	}
+
+	if conflict != nil {
+		clause, err := conflict.clause(meta, columns)
+		if err != nil {
+			return "", err
+		}
+		if err := e.guardConflict(clause, meta); err != nil {
+			return "", err
+		}
+		stmt.OnConflict = clause
+	}
+
+	readBack := autoKey != nil
+	var match []*schema.FieldMeta
+	if readBack && stmt.OnConflict != nil && (stmt.OnConflict.DoNothing || stmt.OnConflict.Where != nil) {
+		// Skipped rows return no key, so keys can only be matched on the conflict target
+		match, readBack = conflictTarget(meta, stmt.OnConflict, autoKey)
+	}
+	if readBack && e.dialect.SupportsReturning() {
+		stmt.Returning = []string{autoKey.DBName}
+		for _, fm := range match {
+			stmt.Returning = append(stmt.Returning, fm.DBName)
+		}
+		return e.insertReturning(stmt, autoKey, match, rows)
+	}
+
+	queryStr, res, err := e.exec(stmt)
+	if err != nil {
+		return queryStr, err
+	}
+
+	if readBack && conflict == nil && len(rows) == 1 {
+		id, err := res.LastInsertId()
+		if err != nil {
+			return queryStr, err
+		}
+		autoKey.DirectSet(rows[0], id)
+	}
+
+	return queryStr, nil
+}
+
+// insertReturning executes an INSERT ... RETURNING and stores the generated
+// keys back into rows. Without match, RETURNING must yield one key per row in
+// VALUES order; with match, each returned key is followed by the match
+// columns and stored into the row holding the same values.
+func (e *Engine) insertReturning(stmt *ast.InsertStmt, key *schema.FieldMeta, match []*schema.FieldMeta,
+	rows []unsafe.Pointer) (string, error) {
+	queryStr, args, err := e.Builder.Visitor().Render(stmt)
+	if err != nil {
+		return "", err
+	}
+
+	if len(match) > 0 {
+		return e.query(stmt, queryStr, args, func(result database.Rows) (int64, error) {
+			return scanMatchedKeys(result, key, match, rows)
+		})
+	}
+
+	return e.query(stmt, queryStr, args, func(result database.Rows) (int64, error) {
+		var n int64
+		for ; n < int64(len(rows)) && result.Next(); n++ {
+			if err := result.Scan(key.PointerMaker(rows[n])); err != nil {
+				return n, err
+			}
+		}
+		if n < int64(len(rows)) {
+			return n, fmt.Errorf("insert returned %d keys for %d rows", n, len(rows))
+		}
+		return n, nil
+	})
+}
+
+// scanMatchedKeys reads (key, match...) rows and stores each key into the row
+// whose match fields hold the returned values.
+func scanMatchedKeys(result database.Rows, key *schema.FieldMeta, match []*schema.FieldMeta,
+	rows []unsafe.Pointer) (int64, error) {
+	byTarget := make(map[string]unsafe.Pointer, len(rows))
+	for _, ptr := range rows {
+		values := make([]any, len(match))
+		for i, fm := range match {
+			values[i] = fm.ValueOf(ptr)
+		}
+		// The first of several rows proposing the same target is the one inserted
+		if k := matchKey(values); byTarget[k] == nil {
+			byTarget[k] = ptr
+		}
+	}
+
+	dest := make([]any, len(match)+1)
+	var n int64
+	for result.Next() {
+		dest[0] = reflect.New(key.Type).Interface()
+		for i, fm := range match {
+			dest[i+1] = reflect.New(fm.Type).Interface()
+		}
+		if err := result.Scan(dest...); err != nil {
+			return n, err
+		}
+
+		values := make([]any, len(match))
+		for i := range match {
+			values[i] = reflect.ValueOf(dest[i+1]).Elem().Interface()
+		}
+		ptr := byTarget[matchKey(values)]
+		if ptr == nil {
+			return n, fmt.Errorf("insert returned a key for %v, which matches no inserted row", values)
+		}
+		reflect.ValueOf(key.PointerMaker(ptr)).Elem().Set(reflect.ValueOf(dest[0]).Elem())
+		n++
+	}
+	return n, nil
+}
+
+// matchKey joins normalised values into a single map key.
+func matchKey(values []any) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = keyString(v)
+	}
+	return strings.Join(parts, "\x00")
+}
+
+// conflictTarget returns the fields of clause's conflict target to match
+// returned keys on. It reports false when the target is a named constraint or
+// unspecified, since returned rows could not be matched to inserted ones, and
+// returns no fields when the target includes the generated key: proposed rows
+// carry no key, so none of them can be skipped.
+func conflictTarget(meta *schema.EntityMeta, clause *ast.OnConflictClause,
+	autoKey *schema.FieldMeta) ([]*schema.FieldMeta, bool) {
+	if clause.Constraint != "" || len(clause.Columns) == 0 {
+		return nil, false
+	}
+	fields := make([]*schema.FieldMeta, len(clause.Columns))
+	for i, col := range clause.Columns {
+		fm := meta.ColumnMap[col]
+		if fm == nil {
+			return nil, false
+		}
+		if fm == autoKey {
+			return nil, true
+		}
+		fields[i] = fm
+	}
+	return fields, true
+}
+
+// entityBatch collects struct addresses from a *T, []*T, []T, *[]*T or *[]T argument.
+func (e *Engine) entityBatch(entities any) (*schema.EntityMeta, []unsafe.Pointer, error) {
+	rv := reflect.ValueOf(entities)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() && rv.Elem().Kind() == reflect.Struct {
+		meta, ptr, err := e.entityMeta(entities)
+		if err != nil {
+			return nil, nil, err
+		}
+		return meta, []unsafe.Pointer{ptr}, nil
+	}
+
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Slice {
+		return nil, nil, fmt.Errorf("entities must be a pointer to a struct or a slice of structs, got %T", entities)
+	}
+	if rv.Len() == 0 {
+		return nil, nil, fmt.Errorf("no entities to write")
+	}
+
+	elemType := rv.Type().Elem()
+	isPtr := elemType.Kind() == reflect.Ptr
+	if isPtr {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		return nil, nil, fmt.Errorf("entities must be a slice of structs, got %T", entities)
+	}
+
+	meta, err := e.schema.Introspect(elemType)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ptrs := make([]unsafe.Pointer, rv.Len())
+	for i := range ptrs {
+		elem := rv.Index(i)
+		if isPtr {
+			if elem.IsNil() {
+				return nil, nil, fmt.Errorf("entity %d is nil", i)
+			}
+			ptrs[i] = elem.UnsafePointer()
+		} else {
+			ptrs[i] = elem.Addr().UnsafePointer()
+		}
+	}
+	return meta, ptrs, nil
+}
+
+// insertFields returns the fields written by an INSERT, leaving out a
+// database-generated key.
+func insertFields(meta *schema.EntityMeta, autoKey *schema.FieldMeta) []*schema.FieldMeta {
+	fields := make([]*schema.FieldMeta, 0, len(meta.Fields))
+	for _, fm := range meta.Fields {
+		if fm != autoKey {
+			fields = append(fields, fm)
+		}
+	}
+	return fields
+}
+
+// generatedKey returns the single-column primary key when it is zero in every
+// row, meaning the database is expected to generate it. Returns nil otherwise.
+func generatedKey(meta *schema.EntityMeta, rows []unsafe.Pointer) *schema.FieldMeta {
+	if len(meta.PrimaryKey) != 1 {
+		return nil
+	}
+	key := meta.PrimaryKey[0]
+	for _, ptr := range rows {
+		if !key.IsZeroIn(ptr) {
+			return nil
+		}
+	}
+	return key
+}
+
+// exec renders stmt and executes it through the middleware chain. Writes
+// bypass the query cache; see SQLVisitor.Render.
+func (e *Engine) exec(stmt ast.Node) (string, database.Result, error) {
+	queryStr, args, err := e.Builder.Visitor().Render(stmt)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var res database.Result
+	st := e.newStatement(stmt, queryStr, args)
+	st.do = func(ctx context.Context, st *Statement) (int64, error) {
+		var err error
+		if res, err = e.querier(false).ExecContext(ctx, st.SQL, st.Args...); err != nil {
+			return 0, err
+		}
+		e.markWrite()
+		n, _ := res.RowsAffected() // Not every driver reports it
+		return n, nil
+	}
+	err = e.dispatch(e.ctx, st)
+	return st.SQL, res, err
+}
+
+// query runs a row-returning statement through the middleware chain. scan
+// consumes the rows and returns how many it read. Returns the executed SQL.
+func (e *Engine) query(node ast.Node, queryStr string, args []any, scan func(rows database.Rows) (int64, error)) (string, error) {
+	st := e.newStatement(node, queryStr, args)
+	st.do = func(ctx context.Context, st *Statement) (int64, error) {
+		read := st.Op == OpSelect
+		rows, err := e.querier(read).QueryContext(ctx, st.SQL, st.Args...)
+		if err != nil {
+			return 0, err
+		}
+		if !read {
+			e.markWrite()
+		}
+		defer rows.Close()
+		return scan(rows)
+	}
+	err := e.dispatch(e.ctx, st)
+	return st.SQL, err
+}
+
+// entityMeta validates that entity is a non-nil pointer to a struct and
+// returns its metadata together with the struct address.
+func (e *Engine) entityMeta(entity any) (*schema.EntityMeta, unsafe.Pointer, error) {
+	rv := reflect.ValueOf(entity)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return nil, nil, fmt.Errorf("entity must be a non-nil pointer to a struct, got %T", entity)
+	}
+
+	meta, err := e.schema.Introspect(rv.Type())
+	if err != nil {
+		return nil, nil, err
+	}
+	return meta, rv.UnsafePointer(), nil
+}
+
+// keyCondition builds the primary key predicate for the given key values:
+// "id" = $1 for single keys, ("a", "b") = ($1, $2) for composite keys.
+func keyCondition(meta *schema.EntityMeta, values []any) ast.Node {
+	if len(meta.PrimaryKey) == 1 {
+		return ast.NewBinaryExpr(
+			ast.NewColumn("", meta.PrimaryKey[0].DBName, ""),
+			ast.OpEqual,
+			ast.NewValue(values[0]),
+		)
+	}
+
+	cols := make([]ast.Node, len(meta.PrimaryKey))
+	vals := make([]ast.Node, len(meta.PrimaryKey))
+	for i, fm := range meta.PrimaryKey {
+		cols[i] = ast.NewColumn("", fm.DBName, "")
+		vals[i] = ast.NewValue(values[i])
+	}
+	return ast.NewBinaryExpr(ast.NewTuple(cols...), ast.OpEqual, ast.NewTuple(vals...))
+}
+
+// updateFields resolves the fields written by an update. Key columns are never
+// written; an empty column list selects every non-key field.
+func updateFields(meta *schema.EntityMeta, columns []string) ([]*schema.FieldMeta, error) {
+	if len(columns) == 0 {
+		fields := make([]*schema.FieldMeta, 0, len(meta.Fields))
+		for _, fm := range meta.Fields {
+			if !meta.IsPrimaryKey(fm.DBName) {
+				fields = append(fields, fm)
+			}
+		}
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("%s has no non-key columns to update", meta.Name)
+		}
+		return fields, nil
+	}
+
+	fields := make([]*schema.FieldMeta, 0, len(columns))
+	for _, col := range columns {
+		fm := lookupField(meta, col)
+		if fm == nil {
+			return nil, fmt.Errorf("%s has no column %s", meta.Name, col)
+		}
+		if meta.IsPrimaryKey(fm.DBName) {
+			return nil, fmt.Errorf("cannot update primary key column %s", fm.DBName)
+		}
+		fields = append(fields, fm)
+	}
+	return fields, nil
+}
+
+// appendMissing appends the fields of extra not already present in fields.
+func appendMissing(fields, extra []*schema.FieldMeta) []*schema.FieldMeta {
+	for _, fm := range extra {
+		if !slices.Contains(fields, fm) {
+			fields = append(fields, fm)
+		}
+	}
+	return fields
+}
+
+// lookupField finds a field by column name, falling back to the Go field name.
+func lookupField(meta *schema.EntityMeta, name string) *schema.FieldMeta {
+	if fm := meta.ColumnMap[name]; fm != nil {
+		return fm
+	}
+	return meta.FieldMap[name]
+}
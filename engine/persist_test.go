@@ -0,0 +1,67 @@
+package engine
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Konsultn-Engineering/enorm/dialect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWritesBypassQueryCache(t *testing.T) {
+	conn := newFakeConn(dialect.NewPostgresDialect())
+	e := New(conn)
+
+	for i := range 20 {
+		// A zero ID goes through INSERT ... RETURNING, a set one through exec
+		conn.db.rows = [][]any{{int64(100 + i)}}
+		_, err := e.Insert(&Note{Title: fmt.Sprint("returning ", i)})
+		require.NoError(t, err)
+		_, err = e.Insert(&Note{ID: int64(i + 1), Title: fmt.Sprint("exec ", i)})
+		require.NoError(t, err)
+		_, err = e.Update(&Note{ID: int64(i + 1), Title: fmt.Sprint("update ", i)})
+		require.NoError(t, err)
+	}
+	assert.Contains(t, conn.db.queries[0], "RETURNING")
+	assert.Zero(t, e.qcache.Stats().Entries)
+
+	// Reads are still cached
+	for range 2 {
+		_, err := e.WhereEq("title", "a").Find(&[]*Note{})
+		require.NoError(t, err)
+	}
+	stats := e.qcache.Stats()
+	assert.Equal(t, 1, stats.Entries)
+	assert.Equal(t, uint64(1), stats.Hits)
+}
+
+func TestWritesResetQueryState(t *testing.T) {
+	d := dialect.NewPostgresDialect()
+	cluster := &fakeCluster{fakeConn: newFakeConn(d), replica: newFakeConn(d)}
+	primary, replica := cluster.fakeConn.db, cluster.replica.db
+	e := New(cluster)
+
+	// Unscoped applies to the Update only, not to the next read
+	_, err := e.Unscoped().Update(&Note{ID: 1, Title: "a"})
+	require.NoError(t, err)
+	_, err = e.Find(&[]*Note{})
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT "notes"."id", "notes"."title", "notes"."deleted_at" FROM "notes" WHERE "notes"."deleted_at" IS NULL`, replica.queries[0])
+
+	// Conditions an Insert ignores are dropped with it
+	_, err = e.WhereEq("title", "zz").Insert(&Note{ID: 2, Title: "b"})
+	require.NoError(t, err)
+	_, err = e.Find(&[]*Note{})
+	require.NoError(t, err)
+	assert.Equal(t, replica.queries[0], replica.queries[1])
+	assert.Empty(t, replica.args[1])
+
+	// So are routing overrides
+	_, err = e.UsePrimary().Upsert(&Note{ID: 3, Title: "c"})
+	require.NoError(t, err)
+	_, err = e.Find(&[]*Note{})
+	require.NoError(t, err)
+	assert.Len(t, replica.queries, 3)
+	assert.Len(t, primary.queries, 3)
+}
@@ -0,0 +1,251 @@
+package engine
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/Konsultn-Engineering/enorm/ast"
+	"github.com/Konsultn-Engineering/enorm/database"
+	"github.com/Konsultn-Engineering/enorm/query"
+	"github.com/Konsultn-Engineering/enorm/schema"
+)
+
+// Preload marks relations to load after the next Find or FindOne.
+// Each relation is loaded with one additional query per related table.
+//
+// Example:
+//
+//	var posts []*Post
+//	_, err := e.Preload("Comments").Find(&posts)
+func (e *Engine) Preload(relations ...string) *Engine {
+	e.preloads = append(e.preloads, relations...)
+	return e
+}
+
+// runPreloads loads every requested relation for the given parent pointers.
+func (e *Engine) runPreloads(meta *schema.EntityMeta, parents []reflect.Value) error {
+	if len(parents) == 0 {
+		return nil
+	}
+
+	for _, name := range e.preloads {
+		rel, ok := meta.Relations[name]
+		if !ok {
+			return fmt.Errorf("preload: %s has no relation %s", meta.Name, name)
+		}
+		if !rel.IsPolymorphic() {
+			return fmt.Errorf("preload: relation %s.%s is not polymorphic; only polymorphic relations can be preloaded", meta.Name, name)
+		}
+
+		var err error
+		switch rel.Kind {
+		case schema.RelationHasMany, schema.RelationHasOne:
+			err = e.preloadMorphMany(meta, rel, parents)
+		case schema.RelationBelongsTo:
+			err = e.preloadMorphTo(meta, rel, parents)
+		}
+		if err != nil {
+			return fmt.Errorf("preload %s.%s: %w", meta.Name, name, err)
+		}
+	}
+
+	return nil
+}
+
+// preloadMorphMany loads children whose type/id columns point at the parents:
+//
+//	SELECT ... FROM comments WHERE commentable_type = $1 AND commentable_id IN (...)
+func (e *Engine) preloadMorphMany(meta *schema.EntityMeta, rel *schema.RelationMeta, parents []reflect.Value) error {
+	pk, err := primaryKeyField(meta)
+	if err != nil {
+		return err
+	}
+
+	childMeta, err := e.schema.Introspect(rel.Target)
+	if err != nil {
+		return err
+	}
+	idField := childMeta.ColumnMap[rel.IDColumn]
+	if idField == nil || childMeta.ColumnMap[rel.TypeColumn] == nil {
+		return fmt.Errorf("%s is missing polymorphic columns %s/%s", childMeta.Name, rel.TypeColumn, rel.IDColumn)
+	}
+
+	ids := collectKeys(parents, pk)
+	children, err := e.loadWhere(childMeta, func(b *query.Builder) {
+		b.Where(rel.TypeColumn, ast.OpEqual, meta.PolymorphicValue())
+		b.Where(rel.IDColumn, ast.OpIn, ids)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Group children by the parent key they reference
+	grouped := make(map[string][]reflect.Value, len(ids))
+	for _, child := range children {
+		key := keyString(fieldValue(child, idField))
+		grouped[key] = append(grouped[key], child)
+	}
+
+	for _, parent := range parents {
+		field := parent.Elem().FieldByIndex(rel.Index)
+		matches := grouped[keyString(fieldValue(parent, pk))]
+		if rel.Kind == schema.RelationHasOne {
+			if len(matches) > 0 {
+				field.Set(adaptPointer(matches[0], field.Type()))
+			}
+			continue
+		}
+		field.Set(buildSlice(matches, field.Type()))
+	}
+
+	return nil
+}
+
+// preloadMorphTo loads the parents referenced by polymorphic type/id columns,
+// issuing one query per concrete parent table.
+func (e *Engine) preloadMorphTo(meta *schema.EntityMeta, rel *schema.RelationMeta, children []reflect.Value) error {
+	typeField := meta.ColumnMap[rel.TypeColumn]
+	idField := meta.ColumnMap[rel.IDColumn]
+	if typeField == nil || idField == nil {
+		return fmt.Errorf("%s is missing polymorphic columns %s/%s", meta.Name, rel.TypeColumn, rel.IDColumn)
+	}
+
+	// Group referenced ids by discriminator value, preserving first-seen order
+	var typeOrder []string
+	idsByType := make(map[string][]any)
+	seen := make(map[string]bool)
+	for _, child := range children {
+		typeValue := fmt.Sprint(fieldValue(child, typeField))
+		id := fieldValue(child, idField)
+		if typeValue == "" || id == nil {
+			continue
+		}
+		if _, ok := idsByType[typeValue]; !ok {
+			typeOrder = append(typeOrder, typeValue)
+		}
+		if key := typeValue + ":" + keyString(id); !seen[key] {
+			seen[key] = true
+			idsByType[typeValue] = append(idsByType[typeValue], id)
+		}
+	}
+
+	loaded := make(map[string]reflect.Value, len(seen))
+	for _, typeValue := range typeOrder {
+		targetType, ok := schema.LookupPolymorphicType(typeValue)
+		if !ok {
+			return fmt.Errorf("no type registered for polymorphic value %q (see schema.RegisterPolymorphicType)", typeValue)
+		}
+
+		targetMeta, err := e.schema.Introspect(targetType)
+		if err != nil {
+			return err
+		}
+		pk, err := primaryKeyField(targetMeta)
+		if err != nil {
+			return err
+		}
+
+		parents, err := e.loadWhere(targetMeta, func(b *query.Builder) {
+			b.Where(pk.DBName, ast.OpIn, idsByType[typeValue])
+		})
+		if err != nil {
+			return err
+		}
+		for _, parent := range parents {
+			loaded[typeValue+":"+keyString(fieldValue(parent, pk))] = parent
+		}
+	}
+
+	for _, child := range children {
+		key := fmt.Sprint(fieldValue(child, typeField)) + ":" + keyString(fieldValue(child, idField))
+		parent, ok := loaded[key]
+		if !ok {
+			continue
+		}
+		field := child.Elem().FieldByIndex(rel.Index)
+		if !parent.Type().AssignableTo(field.Type()) {
+			return fmt.Errorf("%s does not implement %s", parent.Type(), field.Type())
+		}
+		field.Set(parent)
+	}
+
+	return nil
+}
+
+// loadWhere runs a SELECT of all mapped columns for meta, filtered by apply.
+func (e *Engine) loadWhere(meta *schema.EntityMeta, apply func(b *query.Builder)) ([]reflect.Value, error) {
+	b := query.NewBuilder(e.Builder.Schema(), meta.TableName, e.Builder.Visitor())
+	defer b.Release()
+	apply(b)
+	e.applyScopes(b, meta, nil)
+
+	queryStr, args, err := b.Build(meta.TableName, meta.Columns)
+	if err != nil {
+		return nil, err
+	}
+
+	var entities []reflect.Value
+	_, err = e.query(b.Statement(), queryStr, args, func(rows database.Rows) (int64, error) {
+		var err error
+		entities, err = scanEntities(rows, meta)
+		return int64(len(entities)), err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entities, e.afterFind(meta, entities)
+}
+
+// primaryKeyField returns the single-column primary key used to match relations.
+func primaryKeyField(meta *schema.EntityMeta) (*schema.FieldMeta, error) {
+	switch len(meta.PrimaryKey) {
+	case 0:
+		return nil, fmt.Errorf("%s has no primary key", meta.Name)
+	case 1:
+		return meta.PrimaryKey[0], nil
+	default:
+		return nil, fmt.Errorf("%s has a composite primary key %v; polymorphic relations need a single key column",
+			meta.Name, meta.PrimaryKeyColumns())
+	}
+}
+
+// collectKeys returns the distinct values of field across entities.
+func collectKeys(entities []reflect.Value, field *schema.FieldMeta) []any {
+	seen := make(map[string]bool, len(entities))
+	keys := make([]any, 0, len(entities))
+	for _, entity := range entities {
+		v := fieldValue(entity, field)
+		if k := keyString(v); !seen[k] {
+			seen[k] = true
+			keys = append(keys, v)
+		}
+	}
+	return keys
+}
+
+// fieldValue reads a mapped field from a struct pointer.
+func fieldValue(entity reflect.Value, field *schema.FieldMeta) any {
+	return entity.Elem().FieldByIndex(field.Index).Interface()
+}
+
+// keyString normalises key values so that e.g. int64(1) and uint64(1) match.
+func keyString(v any) string {
+	return fmt.Sprint(v)
+}
+
+// adaptPointer converts a *T value to the representation expected by target (*T or T).
+func adaptPointer(ptr reflect.Value, target reflect.Type) reflect.Value {
+	if target.Kind() == reflect.Ptr {
+		return ptr
+	}
+	return ptr.Elem()
+}
+
+// buildSlice assembles matches into a slice of type sliceType ([]*T or []T).
+func buildSlice(matches []reflect.Value, sliceType reflect.Type) reflect.Value {
+	out := reflect.MakeSlice(sliceType, len(matches), len(matches))
+	for i, m := range matches {
+		out.Index(i).Set(adaptPointer(m, sliceType.Elem()))
+	}
+	return out
+}
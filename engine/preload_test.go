@@ -0,0 +1,146 @@
+package engine
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Konsultn-Engineering/enorm/dialect"
+	"github.com/Konsultn-Engineering/enorm/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Comment struct {
+	ID              uint64
+	CommentableType string
+	CommentableID   uint64
+	Body            string
+	Commentable     any `db:"belongs_to;polymorphic:Commentable"`
+}
+
+type Photo struct {
+	ID       uint64
+	URL      string
+	Comments []*Comment `db:"has_many;polymorphic:Commentable"`
+}
+
+type Video struct {
+	ID       uint64
+	Title    string
+	Comments []Comment `db:"has_many;polymorphic:Commentable"`
+}
+
+func init() {
+	schema.RegisterPolymorphicType[Photo]("photo")
+	schema.RegisterPolymorphicType[Video]("video")
+}
+
+func TestPolymorphicRelationTags(t *testing.T) {
+	ctx := schema.New()
+
+	meta, err := ctx.Introspect(reflect.TypeOf(Comment{}))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"id", "commentable_type", "commentable_id", "body"}, meta.Columns)
+
+	rel := meta.Relations["Commentable"]
+	require.NotNil(t, rel)
+	assert.Equal(t, schema.RelationBelongsTo, rel.Kind)
+	assert.True(t, rel.IsPolymorphic())
+	assert.Equal(t, "commentable_type", rel.TypeColumn)
+	assert.Equal(t, "commentable_id", rel.IDColumn)
+	assert.Nil(t, rel.Target)
+
+	meta, err = ctx.Introspect(reflect.TypeOf(Photo{}))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"id", "url"}, meta.Columns)
+	assert.Equal(t, "photo", meta.PolymorphicValue())
+
+	rel = meta.Relations["Comments"]
+	require.NotNil(t, rel)
+	assert.Equal(t, schema.RelationHasMany, rel.Kind)
+	assert.Equal(t, reflect.TypeOf(Comment{}), rel.Target)
+	assert.Equal(t, "commentable_id", rel.IDColumn)
+
+	type concreteParent struct {
+		ID          uint64
+		Commentable Photo `db:"belongs_to;polymorphic:Commentable"`
+	}
+	_, err = ctx.Introspect(reflect.TypeOf(concreteParent{}))
+	assert.ErrorContains(t, err, "must be an interface")
+
+	type scalarMany struct {
+		ID       uint64
+		Comments *Comment `db:"has_many;polymorphic:Commentable"`
+	}
+	_, err = ctx.Introspect(reflect.TypeOf(scalarMany{}))
+	assert.ErrorContains(t, err, "must be a slice")
+}
+
+func TestPreloadMorphTo(t *testing.T) {
+	conn := newFakeConn(dialect.NewPostgresDialect())
+	e := New(conn)
+
+	conn.db.rows = [][]any{
+		{int64(1), "photo", int64(10), "a"},
+		{int64(2), "video", int64(20), "b"},
+		{int64(3), "photo", int64(11), "c"},
+		{int64(4), "photo", int64(10), "d"},
+	}
+	conn.db.pending = [][][]any{
+		{{int64(10), "p10.jpg"}, {int64(11), "p11.jpg"}},
+		{{int64(20), "Intro"}},
+	}
+
+	var comments []*Comment
+	_, err := e.Preload("Commentable").Find(&comments)
+	require.NoError(t, err)
+
+	// One query for the comments, then one per concrete parent table
+	require.Len(t, conn.db.queries, 3)
+	assert.Contains(t, conn.db.queries[1], `FROM "photos" WHERE "photos"."id" IN ($1, $2)`)
+	assert.Equal(t, []any{uint64(10), uint64(11)}, conn.db.args[1])
+	assert.Contains(t, conn.db.queries[2], `FROM "videos" WHERE "videos"."id" IN ($1)`)
+	assert.Equal(t, []any{uint64(20)}, conn.db.args[2])
+
+	require.Len(t, comments, 4)
+	assert.Equal(t, &Photo{ID: 10, URL: "p10.jpg"}, comments[0].Commentable)
+	assert.Equal(t, &Video{ID: 20, Title: "Intro"}, comments[1].Commentable)
+	assert.Equal(t, &Photo{ID: 11, URL: "p11.jpg"}, comments[2].Commentable)
+	assert.Same(t, comments[0].Commentable, comments[3].Commentable)
+}
+
+func TestPreloadMorphMany(t *testing.T) {
+	conn := newFakeConn(dialect.NewPostgresDialect())
+	e := New(conn)
+
+	conn.db.rows = [][]any{{int64(10), "a.jpg"}, {int64(11), "b.jpg"}}
+	conn.db.pending = [][][]any{{
+		{int64(1), "photo", int64(10), "first"},
+		{int64(2), "photo", int64(10), "second"},
+	}}
+
+	var photos []*Photo
+	_, err := e.Preload("Comments").Find(&photos)
+	require.NoError(t, err)
+
+	require.Len(t, conn.db.queries, 2)
+	assert.Contains(t, conn.db.queries[1], `FROM "comments" WHERE "comments"."commentable_type" = $1 AND "comments"."commentable_id" IN ($2, $3)`)
+	assert.Equal(t, []any{"photo", uint64(10), uint64(11)}, conn.db.args[1])
+
+	require.Len(t, photos, 2)
+	require.Len(t, photos[0].Comments, 2)
+	assert.Equal(t, "first", photos[0].Comments[0].Body)
+	assert.Equal(t, "second", photos[0].Comments[1].Body)
+	assert.Empty(t, photos[1].Comments)
+
+	// Value slices are filled the same way
+	conn.db.rows = [][]any{{int64(20), "Intro"}}
+	conn.db.pending = [][][]any{{{int64(3), "video", int64(20), "nice"}}}
+
+	var videos []*Video
+	_, err = e.Preload("Comments").Find(&videos)
+	require.NoError(t, err)
+	assert.Equal(t, []any{"video", uint64(20)}, conn.db.args[3])
+	require.Len(t, videos, 1)
+	assert.Equal(t, []Comment{{ID: 3, CommentableType: "video", CommentableID: 20, Body: "nice"}}, videos[0].Comments)
+}
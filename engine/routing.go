@@ -0,0 +1,107 @@
+package engine
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/Konsultn-Engineering/enorm/connector"
+	"github.com/Konsultn-Engineering/enorm/database"
+)
+
+type readYourWritesKey struct{}
+
+// writeWindow remembers the last write made under a context.
+type writeWindow struct {
+	window    time.Duration
+	lastWrite atomic.Int64 // Unix nanoseconds, 0 before the first write
+}
+
+// ContextWithReadYourWrites returns a copy of ctx under which, once a write
+// has been made, queries of engines built from a cluster read from the
+// primary for window, so that a request sees its own writes despite
+// replication lag. Derive it once per request and pass it to WithContext.
+//
+// Example:
+//
+//	ctx := engine.ContextWithReadYourWrites(r.Context(), 5*time.Second)
+//	db := e.WithContext(ctx)
+//	db.Insert(&comment)
+//	db.WhereEq("post_id", comment.PostID).Find(&comments) // reads from the primary
+func ContextWithReadYourWrites(ctx context.Context, window time.Duration) context.Context {
+	return context.WithValue(ctx, readYourWritesKey{}, &writeWindow{window: window})
+}
+
+// UsePrimary sends the next query to the primary even when the engine is
+// built from a cluster. Writes and transactions always use the primary.
+//
+// Example:
+//
+//	e.UsePrimary().WhereEq("id", id).FindOne(&account)
+func (e *Engine) UsePrimary() *Engine {
+	e.usePrimary = true
+	return e
+}
+
+// MaxStaleness lets the next query read from a cluster replica only if its
+// replication lag is at most d, falling back to the primary when no replica
+// qualifies. Lag is measured by the cluster's health monitor (see
+// connector.PoolConfig.HealthCheckFreq).
+//
+// Example:
+//
+//	e.MaxStaleness(time.Second).WhereEq("user_id", id).Find(&orders)
+func (e *Engine) MaxStaleness(d time.Duration) *Engine {
+	e.maxStaleness = d
+	e.staleBounded = true
+	return e
+}
+
+// querier returns where a statement runs: the engine's transaction, a read
+// replica of a cluster for reads, or the primary.
+func (e *Engine) querier(read bool) database.Querier {
+	if e.tx != nil {
+		return e.tx
+	}
+	if read {
+		return e.reader()
+	}
+	return e.writer()
+}
+
+// reader returns the database a query runs on outside transactions.
+func (e *Engine) reader() database.Database {
+	cluster, ok := e.conn.(connector.ClusterConnection)
+	if !ok || e.usePrimary || e.wroteRecently() {
+		return e.db
+	}
+	ctx := e.ctx
+	if e.staleBounded {
+		ctx = connector.WithMaxStaleness(ctx, e.maxStaleness)
+	}
+	return cluster.Read(ctx).Database()
+}
+
+// writer returns the database writes and transactions run on.
+func (e *Engine) writer() database.Database {
+	if cluster, ok := e.conn.(connector.ClusterConnection); ok {
+		return cluster.Write(e.ctx).Database()
+	}
+	return e.db
+}
+
+// markWrite records a write for ContextWithReadYourWrites.
+func (e *Engine) markWrite() {
+	if w, ok := e.ctx.Value(readYourWritesKey{}).(*writeWindow); ok {
+		w.lastWrite.Store(time.Now().UnixNano())
+	}
+}
+
+func (e *Engine) wroteRecently() bool {
+	w, ok := e.ctx.Value(readYourWritesKey{}).(*writeWindow)
+	if !ok {
+		return false
+	}
+	last := w.lastWrite.Load()
+	return last != 0 && time.Since(time.Unix(0, last)) < w.window
+}
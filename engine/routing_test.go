@@ -0,0 +1,63 @@
+package engine
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Konsultn-Engineering/enorm/dialect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadWriteSplitting(t *testing.T) {
+	d := dialect.NewPostgresDialect()
+	cluster := &fakeCluster{fakeConn: newFakeConn(d), replica: newFakeConn(d)}
+	primary, replica := cluster.fakeConn.db, cluster.replica.db
+	e := New(cluster)
+
+	_, err := e.Find(&[]*Note{})
+	require.NoError(t, err)
+	assert.Len(t, replica.queries, 1)
+	assert.Empty(t, primary.queries)
+
+	_, err = e.UsePrimary().Find(&[]*Note{})
+	require.NoError(t, err)
+	assert.Len(t, primary.queries, 1)
+	_, err = e.Find(&[]*Note{}) // UsePrimary only applies to one query
+	require.NoError(t, err)
+	assert.Len(t, replica.queries, 2)
+
+	err = e.Transaction(func(tx *Engine) error {
+		_, err := tx.Find(&[]*Note{})
+		return err
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"BEGIN", "COMMIT"}, []string{primary.queries[1], primary.queries[3]})
+	assert.Len(t, replica.queries, 2)
+
+	// After a write, reads under a read-your-writes context go to the primary
+	rw := e.WithContext(ContextWithReadYourWrites(context.Background(), time.Minute))
+	_, err = rw.Find(&[]*Note{})
+	require.NoError(t, err)
+	assert.Len(t, replica.queries, 3)
+	_, err = rw.Insert(&Note{ID: 1, Title: "a"})
+	require.NoError(t, err)
+	_, err = rw.Find(&[]*Note{})
+	require.NoError(t, err)
+	assert.Len(t, replica.queries, 3)
+	assert.True(t, strings.HasPrefix(primary.queries[len(primary.queries)-1], "SELECT"))
+
+	// Replicas lagging more than MaxStaleness are skipped for one query
+	cluster.lag = 2 * time.Second
+	_, err = e.MaxStaleness(time.Second).Find(&[]*Note{})
+	require.NoError(t, err)
+	assert.Len(t, replica.queries, 3)
+	_, err = e.MaxStaleness(5 * time.Second).Find(&[]*Note{})
+	require.NoError(t, err)
+	assert.Len(t, replica.queries, 4)
+	_, err = e.Find(&[]*Note{})
+	require.NoError(t, err)
+	assert.Len(t, replica.queries, 5)
+}
@@ -0,0 +1,63 @@
+package engine
+
+import (
+	"reflect"
+
+	"github.com/Konsultn-Engineering/enorm/database"
+	"github.com/Konsultn-Engineering/enorm/schema"
+)
+
+// scanEntities reads every remaining row into newly allocated structs of meta.Type.
+// Returns pointers (*T) to the scanned structs in row order.
+func scanEntities(rows database.Rows, meta *schema.EntityMeta) ([]reflect.Value, error) {
+	colCount := len(meta.Columns)
+
+	ptrs := scanPtrPool.Get().([]any)
+	ptrs = ptrs[:0]
+	for len(ptrs) < colCount {
+		ptrs = append(ptrs, nil)
+	}
+	defer func() {
+		for i := range ptrs {
+			ptrs[i] = nil
+		}
+		scanPtrPool.Put(ptrs[:0])
+	}()
+
+	var results []reflect.Value
+	for rows.Next() {
+		entity := reflect.New(meta.Type)
+		structPtr := entity.UnsafePointer()
+
+		for j, col := range meta.Columns {
+			if fieldMeta := meta.ColumnMap[col]; fieldMeta != nil {
+				ptrs[j] = fieldMeta.PointerMaker(structPtr)
+			} else {
+				var dummy any
+				ptrs[j] = &dummy
+			}
+		}
+
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, err
+		}
+		results = append(results, entity)
+	}
+
+	return results, nil
+}
+
+// afterFind runs the AfterFind hook on loaded entities (*T values).
+func (e *Engine) afterFind(meta *schema.EntityMeta, entities []reflect.Value) error {
+	if !meta.HasHook(schema.HookAfterFind) {
+		return nil
+	}
+
+	ctx := e.hookContext()
+	for _, entity := range entities {
+		if err := meta.RunHook(ctx, schema.HookAfterFind, entity.UnsafePointer()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package engine
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/Konsultn-Engineering/enorm/ast"
+	"github.com/Konsultn-Engineering/enorm/query"
+	"github.com/Konsultn-Engineering/enorm/schema"
+)
+
+// Scopes applies scopes registered with query.RegisterScope to the next query.
+//
+// Example:
+//
+//	e.Scopes("published", "recent").Find(&posts)
+func (e *Engine) Scopes(names ...string) *Engine {
+	e.scopes = append(e.scopes, names...)
+	return e
+}
+
+// WithoutScope skips the named default scopes for the next query. Without
+// names, every default scope is skipped. Soft delete is controlled separately
+// by Unscoped.
+func (e *Engine) WithoutScope(names ...string) *Engine {
+	if len(names) == 0 {
+		e.noDefaults = true
+	}
+	e.withoutScopes = append(e.withoutScopes, names...)
+	return e
+}
+
+// applyScopes rewrites the WHERE clause of b with the predicates every query
+// for meta must carry: the tenant filter, the soft delete filter, default
+// scopes, then the requested named scopes. Each is ANDed onto the clause as a
+// whole.
+func (e *Engine) applyScopes(b *query.Builder, meta *schema.EntityMeta, named []string) {
+	if cond, err := e.tenantCondition(meta); err != nil {
+		b.AddError(err)
+	} else if cond != nil {
+		b.Scope(cond)
+	}
+
+	if meta.SoftDelete != nil && !e.unscoped {
+		op := ast.OpIsNull
+		if e.onlyTrashed {
+			op = ast.OpIsNotNull
+		}
+		b.Scope(ast.NewUnaryExpr(ast.NewColumn(meta.TableName, meta.SoftDelete.DBName, ""), op, false))
+	}
+
+	if !e.noDefaults {
+		for _, s := range query.DefaultScopes(meta.Type) {
+			if !slices.Contains(e.withoutScopes, s.Name) && !slices.Contains(named, s.Name) {
+				b.ApplyScope(s.Fn)
+			}
+		}
+	}
+
+	for _, name := range named {
+		s, ok := query.LookupScope(meta.Type, name)
+		if !ok {
+			b.AddError(fmt.Errorf("%s has no scope %q (see query.RegisterScope)", meta.Name, name))
+			continue
+		}
+		b.ApplyScope(s.Fn)
+	}
+}
@@ -0,0 +1,52 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/Konsultn-Engineering/enorm/ast"
+	"github.com/Konsultn-Engineering/enorm/dialect"
+	"github.com/Konsultn-Engineering/enorm/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Post struct {
+	ID        int64
+	Title     string
+	Published bool
+	Hidden    bool
+	Rank      int
+}
+
+func TestScopes(t *testing.T) {
+	query.RegisterScope[Post]("published", func(b *query.Builder) {
+		b.Where("published", ast.OpEqual, true)
+	})
+	query.RegisterScope[Post]("featured", func(b *query.Builder) {
+		b.Where("rank", ast.OpGreaterThan, 10).OrWhere("title", ast.OpLike, "%!")
+	})
+	query.RegisterDefaultScope[Post]("visible", func(b *query.Builder) {
+		b.Where("hidden", ast.OpEqual, false)
+	})
+
+	conn := newFakeConn(dialect.NewPostgresDialect())
+	e := New(conn)
+	var posts []*Post
+
+	_, err := e.Scopes("published").Find(&posts)
+	require.NoError(t, err)
+	assert.Contains(t, conn.db.queries[0], `WHERE "hidden" = $1 AND "published" = $2`)
+
+	// Scope conditions are grouped so an OR cannot widen the query
+	_, err = e.WhereEq("title", "a").OrWhereEq("title", "b").Scopes("featured").Find(&posts)
+	require.NoError(t, err)
+	assert.Contains(t, conn.db.queries[1],
+		`WHERE ("title" = $1 OR "title" = $2) AND "hidden" = $3 AND ("rank" > $4 OR "title" LIKE $5)`)
+
+	_, err = e.WithoutScope("visible").Find(&posts)
+	require.NoError(t, err)
+	assert.NotContains(t, conn.db.queries[2], "WHERE")
+
+	_, err = e.Scopes("missing").Find(&posts)
+	assert.ErrorContains(t, err, `Post has no scope "missing"`)
+}
@@ -0,0 +1,90 @@
+package engine
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+
+	"github.com/Konsultn-Engineering/enorm/ast"
+	"github.com/Konsultn-Engineering/enorm/schema"
+)
+
+// Unscoped disables soft delete handling for the next operation: queries
+// include soft-deleted rows and Delete removes rows permanently.
+//
+// Example:
+//
+//	e.Unscoped().Find(&posts)   // live and deleted posts
+//	e.Unscoped().Delete(&post)  // same as ForceDelete
+func (e *Engine) Unscoped() *Engine {
+	e.unscoped = true
+	return e
+}
+
+// OnlyTrashed restricts the next query to soft-deleted rows.
+func (e *Engine) OnlyTrashed() *Engine {
+	e.onlyTrashed = true
+	return e
+}
+
+// Restore clears the soft delete field of entity, making the row visible again.
+func (e *Engine) Restore(entity any) (string, error) {
+	defer e.reset()
+
+	meta, ptr, err := e.entityMeta(entity)
+	if err != nil {
+		return "", err
+	}
+	if meta.SoftDelete == nil {
+		return "", fmt.Errorf("%s does not support soft delete", meta.Name)
+	}
+
+	keys, err := meta.KeyValues(ptr)
+	if err != nil {
+		return "", err
+	}
+
+	stmt := ast.NewUpdateStmt(e.table(meta))
+	defer stmt.Release()
+	stmt.AddSet(meta.SoftDelete.DBName, ast.NewValue(nil))
+	stmt.AddWhereCondition(keyCondition(meta, keys), ast.OpAnd)
+	if err := e.scopeTenant(stmt, meta); err != nil {
+		return "", err
+	}
+
+	queryStr, _, err := e.exec(stmt)
+	if err != nil {
+		return queryStr, err
+	}
+
+	reflect.NewAt(meta.SoftDelete.Type, unsafe.Add(ptr, meta.SoftDelete.Offset)).Elem().SetZero()
+	return queryStr, nil
+}
+
+// ForceDelete permanently removes entity's row, bypassing soft delete.
+func (e *Engine) ForceDelete(entity any) (string, error) {
+	return e.Unscoped().Delete(entity)
+}
+
+// softDelete marks the row matching keys as deleted. Rows that are already
+// deleted keep their original deletion time.
+func (e *Engine) softDelete(meta *schema.EntityMeta, ptr unsafe.Pointer, keys []any) (string, error) {
+	field := meta.SoftDelete
+	now := e.schema.Now()
+
+	stmt := ast.NewUpdateStmt(e.table(meta))
+	defer stmt.Release()
+	stmt.AddSet(field.DBName, ast.NewValue(field.TimestampValue(now)))
+	stmt.AddWhereCondition(keyCondition(meta, keys), ast.OpAnd)
+	stmt.AddWhereCondition(ast.NewUnaryExpr(ast.NewColumn("", field.DBName, ""), ast.OpIsNull, false), ast.OpAnd)
+	if err := e.scopeTenant(stmt, meta); err != nil {
+		return "", err
+	}
+
+	queryStr, _, err := e.exec(stmt)
+	if err != nil {
+		return queryStr, err
+	}
+	field.SetTimestamp(ptr, now)
+	return queryStr, nil
+}
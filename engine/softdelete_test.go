@@ -0,0 +1,63 @@
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Konsultn-Engineering/enorm/dialect"
+	"github.com/Konsultn-Engineering/enorm/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Note struct {
+	ID        int64
+	Title     string
+	DeletedAt *time.Time
+}
+
+func TestSoftDelete(t *testing.T) {
+	frozen := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	conn := newFakeConn(dialect.NewPostgresDialect())
+	e := New(conn, WithSchema(schema.New(schema.WithClock(func() time.Time { return frozen }))))
+
+	note := Note{ID: 7}
+	_, err := e.Delete(&note)
+	require.NoError(t, err)
+	assert.Equal(t, `UPDATE "notes" SET "deleted_at" = $1 WHERE "id" = $2 AND "deleted_at" IS NULL`, conn.db.queries[0])
+	require.NotNil(t, note.DeletedAt)
+	assert.Equal(t, frozen, *note.DeletedAt)
+
+	_, err = e.Restore(&note)
+	require.NoError(t, err)
+	assert.Equal(t, `UPDATE "notes" SET "deleted_at" = $1 WHERE "id" = $2`, conn.db.queries[1])
+	assert.Equal(t, []any{nil, int64(7)}, conn.db.args[1])
+	assert.Nil(t, note.DeletedAt)
+
+	_, err = e.ForceDelete(&note)
+	require.NoError(t, err)
+	assert.Equal(t, `DELETE FROM "notes" WHERE "id" = $1`, conn.db.queries[2])
+}
+
+func TestSoftDeleteScopesQueries(t *testing.T) {
+	conn := newFakeConn(dialect.NewPostgresDialect())
+	e := New(conn)
+
+	var notes []*Note
+	_, err := e.WhereEq("title", "a").OrWhereEq("title", "b").Find(&notes)
+	require.NoError(t, err)
+	assert.Contains(t, conn.db.queries[0], `WHERE ("title" = $1 OR "title" = $2) AND "notes"."deleted_at" IS NULL`)
+
+	_, err = e.OnlyTrashed().Find(&notes)
+	require.NoError(t, err)
+	assert.Contains(t, conn.db.queries[1], `WHERE "notes"."deleted_at" IS NOT NULL`)
+
+	// Overrides and conditions apply to a single query only
+	_, err = e.Unscoped().Find(&notes)
+	require.NoError(t, err)
+	assert.NotContains(t, conn.db.queries[2], "WHERE")
+
+	_, err = e.Find(&notes)
+	require.NoError(t, err)
+	assert.Contains(t, conn.db.queries[3], `WHERE "notes"."deleted_at" IS NULL`)
+}
@@ -0,0 +1,76 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Konsultn-Engineering/enorm/dialect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Invoice struct {
+	ID       int64
+	TenantID int64 `db:"tenant_id;tenant"`
+	Status   string
+}
+
+func TestTenantScoping(t *testing.T) {
+	conn := newFakeConn(dialect.NewPostgresDialect())
+	e := New(conn)
+	var invoices []*Invoice
+
+	_, err := e.Find(&invoices)
+	assert.ErrorIs(t, err, ErrNoTenant)
+	_, err = e.Insert(&Invoice{ID: 1})
+	assert.ErrorIs(t, err, ErrNoTenant)
+
+	tenant := e.ForTenant(int64(42))
+	_, err = tenant.WhereEq("status", "open").OrWhereEq("status", "late").Find(&invoices)
+	require.NoError(t, err)
+	assert.Contains(t, conn.db.queries[0], `WHERE ("status" = $1 OR "status" = $2) AND "invoices"."tenant_id" = $3`)
+	assert.Equal(t, []any{"open", "late", int64(42)}, conn.db.args[0])
+
+	inv := Invoice{ID: 1}
+	_, err = tenant.Insert(&inv)
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), inv.TenantID)
+
+	_, err = tenant.Update(&inv)
+	require.NoError(t, err)
+	assert.Equal(t, `UPDATE "invoices" SET "tenant_id" = $1, "status" = $2 WHERE "id" = $3 AND "invoices"."tenant_id" = $4`, conn.db.queries[2])
+
+	_, err = tenant.Delete(&inv)
+	require.NoError(t, err)
+	assert.Equal(t, `DELETE FROM "invoices" WHERE "id" = $1 AND "invoices"."tenant_id" = $2`, conn.db.queries[3])
+
+	_, err = tenant.Insert(&Invoice{ID: 2, TenantID: 7})
+	assert.ErrorContains(t, err, "Invoice belongs to tenant 7, not 42")
+
+	// The tenant can also travel in the context
+	ctxTenant := e.WithContext(ContextWithTenant(context.Background(), int64(7)))
+	_, err = ctxTenant.Find(&invoices)
+	require.NoError(t, err)
+	assert.Equal(t, []any{int64(7)}, conn.db.args[4])
+}
+
+func TestTenantSchema(t *testing.T) {
+	conn := newFakeConn(dialect.NewPostgresDialect())
+	e := New(conn, WithTenantSchema(func(tenant any) string { return fmt.Sprintf("tenant_%v", tenant) }))
+
+	var notes []*Note
+	tenant := e.ForTenant(3)
+	_, err := tenant.InnerJoin("authors", "author_id", "id").Find(&notes)
+	require.NoError(t, err)
+	assert.Contains(t, conn.db.queries[0], `FROM "tenant_3"."notes" JOIN "tenant_3"."authors"`)
+
+	_, err = tenant.Insert(&Note{ID: 1})
+	require.NoError(t, err)
+	assert.Contains(t, conn.db.queries[1], `INSERT INTO "tenant_3"."notes"`)
+
+	// Engines without a tenant keep tables unqualified
+	_, err = e.Find(&notes)
+	require.NoError(t, err)
+	assert.Contains(t, conn.db.queries[2], `FROM "notes"`)
+}
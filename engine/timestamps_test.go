@@ -0,0 +1,68 @@
+package engine
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/Konsultn-Engineering/enorm/dialect"
+	"github.com/Konsultn-Engineering/enorm/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Article struct {
+	ID        int64
+	Title     string
+	CreatedAt time.Time    `db:"created_at;auto_now_add"`
+	UpdatedAt *time.Time   `db:"updated_at;auto_now"`
+	Touched   sql.NullTime `db:"touched;auto_now"`
+	Stamp     int64        `db:"stamp;auto_now"`
+}
+
+func TestTimestamps(t *testing.T) {
+	frozen := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	conn := newFakeConn(dialect.NewPostgresDialect())
+	e := New(conn, WithSchema(schema.New(schema.WithClock(func() time.Time { return frozen }))))
+
+	article := Article{ID: 1, Title: "a"}
+	_, err := e.Insert(&article)
+	require.NoError(t, err)
+	assert.Equal(t, frozen, article.CreatedAt)
+	require.NotNil(t, article.UpdatedAt)
+	assert.Equal(t, frozen, *article.UpdatedAt)
+	assert.Equal(t, sql.NullTime{Time: frozen, Valid: true}, article.Touched)
+	assert.Equal(t, frozen.Unix(), article.Stamp)
+
+	// auto_now columns are written even when not requested; auto_now_add is not
+	later := frozen.Add(time.Hour)
+	e = New(conn, WithSchema(schema.New(schema.WithClock(func() time.Time { return later }))))
+	_, err = e.Update(&article, "title")
+	require.NoError(t, err)
+	assert.Equal(t, frozen, article.CreatedAt)
+	assert.Equal(t, later, *article.UpdatedAt)
+	assert.Equal(t,
+		`UPDATE "articles" SET "title" = $1, "updated_at" = $2, "touched" = $3, "stamp" = $4 WHERE "id" = $5`,
+		conn.db.queries[len(conn.db.queries)-1])
+}
+
+func TestUpdateWhereInjectsAutoNow(t *testing.T) {
+	frozen := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	conn := newFakeConn(dialect.NewPostgresDialect())
+	e := New(conn, WithSchema(schema.New(schema.WithClock(func() time.Time { return frozen }))))
+
+	_, err := e.WhereEq("title", "draft").UpdateWhere(&Article{}, map[string]any{"Title": "final", "stamp": int64(7)})
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		`UPDATE "articles" SET "title" = $1, "stamp" = $2, "updated_at" = $3, "touched" = $4 WHERE "title" = $5`,
+		conn.db.queries[0])
+	assert.Equal(t, int64(7), conn.db.args[0][1])
+	assert.Equal(t, &frozen, conn.db.args[0][2])
+	assert.Equal(t, "draft", conn.db.args[0][4])
+
+	// Conditions do not leak into the next statement
+	_, err = e.UpdateWhere(&Article{}, map[string]any{"title": "x"})
+	require.NoError(t, err)
+	assert.NotContains(t, conn.db.queries[1], "WHERE")
+}
@@ -0,0 +1,60 @@
+package engine
+
+import (
+	"context"
+
+	"github.com/Konsultn-Engineering/enorm/trace"
+)
+
+// WithTracer traces every statement the engine runs, and every transaction it
+// opens, as a span of t. Statement spans are named after the operation and
+// table ("SELECT users") and carry the db.system, db.statement, db.operation
+// and db.sql.table attributes; statements run in a transaction are children of
+// the transaction span. Argument values are never recorded.
+//
+// Spans are children of the span in the engine's context, see WithContext.
+// To also trace connection acquisition, set connector.Config.Tracer.
+func WithTracer(t trace.Tracer) Option {
+	return func(e *Engine) {
+		e.tracer = t
+		e.Use(e.traceMiddleware())
+	}
+}
+
+func (e *Engine) traceMiddleware() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, st *Statement) (err error) {
+			name := string(st.Op)
+			if st.Table != "" {
+				name += " " + st.Table
+			}
+			ctx, span := e.tracer.Start(ctx, name,
+				trace.String(trace.DBSystem, e.dialect.Name()),
+				trace.String(trace.DBStatement, st.SQL),
+				trace.String(trace.DBOperation, string(st.Op)),
+				trace.String(trace.DBSQLTable, st.Table),
+			)
+			defer func() { trace.End(span, err) }()
+			return next(ctx, st)
+		}
+	}
+}
+
+// startTxSpan starts the span of a transaction and binds e to it until end is
+// called. Without a tracer it does nothing.
+func (e *Engine) startTxSpan() (end func(err error)) {
+	if e.tracer == nil {
+		return func(error) {}
+	}
+
+	parent := e.ctx
+	ctx, span := e.tracer.Start(parent, "TRANSACTION",
+		trace.String(trace.DBSystem, e.dialect.Name()),
+		trace.String(trace.DBOperation, "BEGIN"),
+	)
+	e.ctx = ctx
+	return func(err error) {
+		e.ctx = parent
+		trace.End(span, err)
+	}
+}
@@ -0,0 +1,46 @@
+package engine
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Konsultn-Engineering/enorm/dialect"
+	"github.com/Konsultn-Engineering/enorm/trace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracer(t *testing.T) {
+	conn := newFakeConn(dialect.NewPostgresDialect())
+	tracer := &fakeTracer{}
+	e := New(conn, WithTracer(tracer))
+
+	_, err := e.WhereEq("title", "secret").Find(&[]*Note{})
+	require.NoError(t, err)
+	require.Len(t, tracer.spans, 1)
+	span := tracer.spans[0]
+	assert.Equal(t, "SELECT notes", span.name)
+	assert.Equal(t, map[string]any{
+		trace.DBSystem:    "postgresql",
+		trace.DBStatement: conn.db.queries[0],
+		trace.DBOperation: "SELECT",
+		trace.DBSQLTable:  "notes",
+	}, span.attrs)
+	assert.True(t, span.ended)
+
+	err = e.Transaction(func(tx *Engine) error {
+		if _, err := tx.Update(&Note{ID: 1, Title: "a"}); err != nil {
+			return err
+		}
+		return errors.New("abort")
+	})
+	require.EqualError(t, err, "abort")
+	require.Len(t, tracer.spans, 3)
+	txSpan, update := tracer.spans[1], tracer.spans[2]
+	assert.Equal(t, "TRANSACTION", txSpan.name)
+	assert.EqualError(t, txSpan.err, "abort")
+	assert.True(t, txSpan.ended)
+	assert.Equal(t, "UPDATE notes", update.name)
+	assert.Same(t, txSpan, update.parent)
+	assert.NoError(t, update.err)
+}
@@ -0,0 +1,116 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Konsultn-Engineering/enorm/database"
+	"github.com/Konsultn-Engineering/enorm/schema"
+)
+
+type engineKey struct{}
+
+// FromContext returns the engine stored in a lifecycle hook's context. It is
+// bound to the transaction of the operation that runs the hook, so writes made
+// through it commit or roll back together with that operation. Returns nil
+// outside hooks.
+//
+// Example:
+//
+//	func (o *Order) AfterCreate(ctx context.Context) error {
+//	    _, err := engine.FromContext(ctx).Insert(&AuditLog{OrderID: o.ID, Action: "created"})
+//	    return err
+//	}
+func FromContext(ctx context.Context) *Engine {
+	e, _ := ctx.Value(engineKey{}).(*Engine)
+	return e
+}
+
+// Transaction runs fn in a database transaction. Every statement issued
+// through the engine passed to fn runs on the transaction, which is committed
+// when fn returns nil and rolled back when it returns an error or panics.
+// Calling Transaction on an engine that is already in one runs fn in the
+// existing transaction.
+//
+// Example:
+//
+//	err := e.Transaction(func(tx *engine.Engine) error {
+//	    if _, err := tx.Update(&from, "balance"); err != nil {
+//	        return err
+//	    }
+//	    _, err := tx.Update(&to, "balance")
+//	    return err
+//	})
+func (e *Engine) Transaction(fn func(tx *Engine) error) error {
+	if e.tx != nil {
+		return fn(e)
+	}
+
+	d := e.derive()
+	return d.inTx(func() error { return fn(d) })
+}
+
+// inTx runs fn in a new transaction bound to e.
+func (e *Engine) inTx(fn func() error) (err error) {
+	end := e.startTxSpan()
+	defer func() { end(err) }()
+
+	db := e.writer()
+	b, ok := db.(database.Beginner)
+	if !ok {
+		return fmt.Errorf("database %T does not support transactions", db)
+	}
+	tx, err := b.BeginTx(e.ctx)
+	if err != nil {
+		return err
+	}
+	return e.runTx(tx, fn)
+}
+
+// runTx binds e to tx while fn runs, then commits, or rolls back when fn
+// fails or panics.
+func (e *Engine) runTx(tx database.Tx, fn func() error) error {
+	e.tx = tx
+	defer func() {
+		e.tx = nil
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return errors.Join(err, rbErr)
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+// withHooks runs a write for meta. When the entity implements any of hooks, fn
+// runs in a transaction (unless the engine is already in one, or the database
+// has none) and receives a context carrying an engine bound to it.
+func (e *Engine) withHooks(meta *schema.EntityMeta, hooks schema.Hook, fn func(ctx context.Context) error) error {
+	if !meta.HasHook(hooks) {
+		return fn(e.ctx)
+	}
+
+	return e.atomically(func() error { return fn(e.hookContext()) })
+}
+
+// atomically runs fn in a transaction bound to e, unless e is already in one
+// or the database has none, in which case fn runs as is.
+func (e *Engine) atomically(fn func() error) error {
+	if _, ok := e.writer().(database.Beginner); e.tx != nil || !ok {
+		return fn()
+	}
+	return e.inTx(fn)
+}
+
+// hookContext returns the context passed to lifecycle hooks, carrying an
+// engine that shares e's transaction but not its query state.
+func (e *Engine) hookContext() context.Context {
+	return context.WithValue(e.ctx, engineKey{}, e.derive())
+}
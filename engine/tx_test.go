@@ -0,0 +1,28 @@
+package engine
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Konsultn-Engineering/enorm/dialect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransaction(t *testing.T) {
+	conn := newFakeConn(dialect.NewPostgresDialect())
+	e := New(conn)
+
+	err := e.Transaction(func(tx *Engine) error {
+		_, err := tx.Update(&Note{ID: 1, Title: "a"})
+		return err
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "COMMIT", conn.db.queries[2])
+
+	err = e.Transaction(func(tx *Engine) error {
+		return errors.New("abort")
+	})
+	assert.EqualError(t, err, "abort")
+	assert.Equal(t, []string{"BEGIN", "ROLLBACK"}, conn.db.queries[3:])
+}
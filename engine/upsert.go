@@ -0,0 +1,143 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/Konsultn-Engineering/enorm/ast"
+	"github.com/Konsultn-Engineering/enorm/schema"
+)
+
+// OnConflict configures how Upsert resolves unique key conflicts.
+//
+// PostgreSQL renders ON CONFLICT (...) DO UPDATE SET col = EXCLUDED.col;
+// MySQL and TiDB render ON DUPLICATE KEY UPDATE col = VALUES(col), which
+// matches any unique key and supports neither Constraint nor Where.
+type OnConflict struct {
+	// Columns is the conflict target. Defaults to the primary key columns,
+	// or to any unique constraint when DoNothing is set.
+	Columns []string
+	// Constraint targets a named constraint instead of Columns (PostgreSQL only).
+	Constraint string
+	// DoNothing skips conflicting rows instead of updating them.
+	DoNothing bool
+	// DoUpdate lists the columns overwritten with the proposed row's values.
+	// Defaults to every inserted column outside the conflict target, except
+	// the primary key, tenant and auto_now_add columns, so the existing row
+	// keeps its identity, owner and creation time.
+	DoUpdate []string
+	// Where restricts which conflicting rows are updated (PostgreSQL only).
+	// Reference the proposed row through the "excluded" table, e.g.
+	// ast.NewColumn("excluded", "version", "").
+	Where ast.Node
+}
+
+// Upsert inserts entity, updating (or skipping) rows that conflict on a unique key.
+// entity may be a pointer to a struct or a slice for a bulk multi-row upsert.
+// Without an OnConflict, conflicts on the primary key update every other column.
+// Generated keys are read back into the entities; rows skipped by DoNothing or
+// Where keep a zero key, and no keys are read back for a skipping upsert that
+// targets a Constraint rather than Columns.
+//
+// Example:
+//
+//	e.Upsert(&user, engine.OnConflict{Columns: []string{"email"}, DoUpdate: []string{"name"}})
+//	e.Upsert(users, engine.OnConflict{Constraint: "users_email_key", DoNothing: true})
+func (e *Engine) Upsert(entity any, conflict ...OnConflict) (string, error) {
+	defer e.reset()
+	var c OnConflict
+	if len(conflict) > 0 {
+		c = conflict[0]
+	}
+	return e.insert(entity, &c)
+}
+
+// clause resolves column and field names against meta and builds the AST clause.
+func (c *OnConflict) clause(meta *schema.EntityMeta, insertColumns []string) (*ast.OnConflictClause, error) {
+	clause := &ast.OnConflictClause{
+		Constraint: c.Constraint,
+		DoNothing:  c.DoNothing,
+	}
+
+	if c.Constraint == "" {
+		if len(c.Columns) == 0 {
+			// A bare DO NOTHING covers every unique constraint
+			if !c.DoNothing {
+				clause.Columns = meta.PrimaryKeyColumns()
+			}
+		} else {
+			cols, err := resolveColumns(meta, c.Columns)
+			if err != nil {
+				return nil, err
+			}
+			clause.Columns = cols
+		}
+	}
+
+	if !c.DoNothing {
+		if len(c.DoUpdate) > 0 {
+			cols, err := resolveColumns(meta, c.DoUpdate)
+			if err != nil {
+				return nil, err
+			}
+			clause.Update = cols
+		} else {
+			keep := make(map[string]bool, len(clause.Columns)+len(meta.PrimaryKey))
+			for _, col := range clause.Columns {
+				keep[col] = true
+			}
+			for _, fm := range meta.PrimaryKey {
+				keep[fm.DBName] = true
+			}
+			if meta.Tenant != nil {
+				keep[meta.Tenant.DBName] = true
+			}
+			for _, col := range insertColumns {
+				if fm := meta.ColumnMap[col]; !keep[col] && !fm.Tag.AutoNowAdd {
+					clause.Update = append(clause.Update, col)
+				}
+			}
+		}
+		// Nothing left to update renders as DO NOTHING
+		clause.DoNothing = len(clause.Update) == 0
+	}
+
+	if c.Where != nil {
+		cond := ast.NewWhereClause(c.Where, ast.OpAnd)
+		clause.Where = &ast.WhereClause{First: cond, Tail: cond}
+	}
+
+	return clause, nil
+}
+
+// resolveColumns maps column or Go field names to column names.
+func resolveColumns(meta *schema.EntityMeta, names []string) ([]string, error) {
+	cols := make([]string, len(names))
+	for i, name := range names {
+		fm := lookupField(meta, name)
+		if fm == nil {
+			return nil, fmt.Errorf("%s has no column %s", meta.Name, name)
+		}
+		cols[i] = fm.DBName
+	}
+	return cols, nil
+}
+
+// guardConflict keeps DO UPDATE from overwriting a conflicting row that belongs
+// to another tenant by adding the tenant filter to its condition. MySQL and
+// TiDB cannot condition the update, so their unique keys on tenant-scoped
+// tables must include the tenant column.
+func (e *Engine) guardConflict(clause *ast.OnConflictClause, meta *schema.EntityMeta) error {
+	cond, err := e.tenantCondition(meta)
+	if err != nil || cond == nil || clause.DoNothing || !e.dialect.SupportsOnConflict() {
+		return err
+	}
+
+	node := ast.NewWhereClause(cond, ast.OpAnd)
+	if clause.Where == nil {
+		clause.Where = &ast.WhereClause{First: node, Tail: node}
+	} else {
+		clause.Where.Tail.Next = node
+		clause.Where.Tail = node
+	}
+	return nil
+}
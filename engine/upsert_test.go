@@ -0,0 +1,125 @@
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Konsultn-Engineering/enorm/dialect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Subscriber struct {
+	ID        string `db:"primary;generator:uuid"`
+	Email     string
+	Name      string
+	CreatedAt time.Time `db:"created_at;auto_now_add"`
+	UpdatedAt time.Time `db:"updated_at;auto_now"`
+}
+
+func TestUpsertDefaultUpdateKeepsIdentity(t *testing.T) {
+	conn := newFakeConn(dialect.NewPostgresDialect())
+	e := New(conn)
+
+	_, err := e.Upsert(&Subscriber{Email: "a@example.com", Name: "A"}, OnConflict{Columns: []string{"Email"}})
+	require.NoError(t, err)
+	assert.Equal(t, `INSERT INTO "subscribers" ("id", "email", "name", "created_at", "updated_at") VALUES ($1, $2, $3, $4, $5) `+
+		`ON CONFLICT ("email") DO UPDATE SET "name" = EXCLUDED."name", "updated_at" = EXCLUDED."updated_at"`, conn.db.queries[0])
+
+	conn = newFakeConn(dialect.NewMySQLDialect())
+	e = New(conn)
+	_, err = e.Upsert(&Subscriber{Email: "a@example.com", Name: "A"}, OnConflict{Columns: []string{"email"}})
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO `subscribers` (`id`, `email`, `name`, `created_at`, `updated_at`) VALUES (?, ?, ?, ?, ?) "+
+		"ON DUPLICATE KEY UPDATE `name` = VALUES(`name`), `updated_at` = VALUES(`updated_at`)", conn.db.queries[0])
+}
+
+func TestUpsertWithNothingToUpdate(t *testing.T) {
+	conn := newFakeConn(dialect.NewPostgresDialect())
+	e := New(conn)
+
+	type Tag struct {
+		ID        int64
+		CreatedAt time.Time `db:"created_at;auto_now_add"`
+	}
+	_, err := e.Upsert(&Tag{ID: 1})
+	require.NoError(t, err)
+	assert.Equal(t, `INSERT INTO "tags" ("id", "created_at") VALUES ($1, $2) ON CONFLICT ("id") DO NOTHING`, conn.db.queries[0])
+}
+
+type Contact struct {
+	ID    int64
+	Email string
+	Name  string
+}
+
+func TestUpsertMatchesReturnedKeysOnConflictTarget(t *testing.T) {
+	conn := newFakeConn(dialect.NewPostgresDialect())
+	e := New(conn)
+
+	// a@example.com already exists and is skipped, so only b's key comes back
+	conn.db.rows = [][]any{{int64(9), "b@example.com"}}
+	contacts := []*Contact{{Email: "a@example.com"}, {Email: "b@example.com"}}
+	_, err := e.Upsert(contacts, OnConflict{Columns: []string{"email"}, DoNothing: true})
+	require.NoError(t, err)
+	assert.Equal(t, `INSERT INTO "contacts" ("email", "name") VALUES ($1, $2), ($3, $4) `+
+		`ON CONFLICT ("email") DO NOTHING RETURNING "id", "email"`, conn.db.queries[0])
+	assert.Zero(t, contacts[0].ID)
+	assert.Equal(t, int64(9), contacts[1].ID)
+
+	// A returned row that matches nothing proposed is an error
+	conn.db.rows = [][]any{{int64(10), "c@example.com"}}
+	_, err = e.Upsert(&Contact{Email: "d@example.com"}, OnConflict{Columns: []string{"email"}, DoNothing: true})
+	assert.ErrorContains(t, err, "matches no inserted row")
+
+	// Rows skipped through a named constraint cannot be matched, so keys are not read back
+	conn.db.queries = nil
+	conn.db.rows = nil
+	contact := Contact{Email: "a@example.com"}
+	_, err = e.Upsert(&contact, OnConflict{Constraint: "contacts_email_key", DoNothing: true})
+	require.NoError(t, err)
+	assert.NotContains(t, conn.db.queries[0], "RETURNING")
+	assert.Zero(t, contact.ID)
+}
+
+func TestInsertFailsOnMissingReturnedKeys(t *testing.T) {
+	conn := newFakeConn(dialect.NewPostgresDialect())
+	e := New(conn)
+
+	conn.db.rows = [][]any{{int64(1)}}
+	_, err := e.Insert([]*Contact{{Email: "a@example.com"}, {Email: "b@example.com"}})
+	assert.EqualError(t, err, "insert returned 1 keys for 2 rows")
+}
+
+type Coupon struct {
+	ID       int64
+	TenantID int64 `db:"tenant_id;tenant"`
+	Code     string
+	Percent  int64
+}
+
+func TestUpsertSkipsOtherTenantsRows(t *testing.T) {
+	conn := newFakeConn(dialect.NewPostgresDialect())
+	e := New(conn).ForTenant(int64(42))
+
+	// SAVE10 is taken by another tenant: the guard skips it and returns no key
+	conn.db.rows = [][]any{{int64(5), "SAVE20"}}
+	coupons := []Coupon{{Code: "SAVE10", Percent: 10}, {Code: "SAVE20", Percent: 20}}
+	_, err := e.Upsert(coupons, OnConflict{Columns: []string{"code"}})
+	require.NoError(t, err)
+	assert.Equal(t, `INSERT INTO "coupons" ("tenant_id", "code", "percent") VALUES ($1, $2, $3), ($4, $5, $6) `+
+		`ON CONFLICT ("code") DO UPDATE SET "percent" = EXCLUDED."percent" WHERE "coupons"."tenant_id" = $7 `+
+		`RETURNING "id", "code"`, conn.db.queries[0])
+	assert.Equal(t, int64(42), conn.db.args[0][6])
+	assert.Zero(t, coupons[0].ID)
+	assert.Equal(t, int64(5), coupons[1].ID)
+
+	// New rows cannot conflict on a key they do not carry, so keys come back in order
+	conn.db.rows = [][]any{{int64(6)}, {int64(7)}}
+	fresh := []Coupon{{Code: "NEW1"}, {Code: "NEW2"}}
+	_, err = e.Upsert(fresh)
+	require.NoError(t, err)
+	assert.Contains(t, conn.db.queries[1], `ON CONFLICT ("id") DO UPDATE SET "code" = EXCLUDED."code", "percent" = EXCLUDED."percent" WHERE`)
+	assert.Contains(t, conn.db.queries[1], `RETURNING "id"`)
+	assert.Equal(t, []int64{6, 7}, []int64{fresh[0].ID, fresh[1].ID})
+}
@@ -0,0 +1,68 @@
+package engine
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Konsultn-Engineering/enorm/dialect"
+	"github.com/Konsultn-Engineering/enorm/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Member struct {
+	ID     int64
+	Handle string  `db:"handle;min_length:3;max_length:8"`
+	Role   string  `db:"role;enum:admin|editor"`
+	Bio    *string `db:"bio;max_length:5"`
+}
+
+func (m *Member) Validate() error {
+	if m.Role == "admin" && m.Handle == "root" {
+		return errors.New("root cannot be an admin")
+	}
+	return nil
+}
+
+func TestInsertValidation(t *testing.T) {
+	conn := newFakeConn(dialect.NewPostgresDialect())
+	e := New(conn)
+
+	bio := "too long"
+	_, err := e.Insert(&Member{ID: 1, Handle: "ab", Role: "owner", Bio: &bio})
+
+	var verrs schema.ValidationErrors
+	require.ErrorAs(t, err, &verrs)
+	require.Len(t, verrs, 3)
+	assert.Equal(t, schema.ValidationError{
+		Field: "Handle", Column: "handle", Rule: schema.RuleMinLength, Value: "ab", Detail: "length 2 is below 3",
+	}, verrs[0])
+	assert.Equal(t, schema.RuleEnum, verrs[1].Rule)
+	assert.Equal(t, "owner", verrs[1].Value)
+	assert.Equal(t, schema.RuleMaxLength, verrs[2].Rule)
+	assert.Empty(t, conn.db.queries, "nothing is sent when validation fails")
+
+	_, err = e.Insert(&Member{ID: 1, Handle: "root", Role: "admin"})
+	require.ErrorAs(t, err, &verrs)
+	assert.Equal(t, schema.RuleCustom, verrs[0].Rule)
+
+	_, err = e.Insert(&Member{ID: 1, Handle: "alice", Role: "editor"})
+	assert.NoError(t, err)
+}
+
+func TestValidationCanBeDisabled(t *testing.T) {
+	conn := newFakeConn(dialect.NewPostgresDialect())
+	e := New(conn, WithSchema(schema.New(schema.WithValidation(false))))
+
+	_, err := e.Insert(&Member{ID: 1, Handle: "ab", Role: "owner"})
+	assert.NoError(t, err)
+}
+
+func TestUpdateWhereValidatesValues(t *testing.T) {
+	e := New(newFakeConn(dialect.NewPostgresDialect()))
+
+	_, err := e.WhereEq("id", 1).UpdateWhere(&Member{}, map[string]any{"role": "owner"})
+	var verrs schema.ValidationErrors
+	require.ErrorAs(t, err, &verrs)
+	assert.Equal(t, "role", verrs[0].Column)
+}
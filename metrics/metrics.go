@@ -0,0 +1,291 @@
+// Package metrics collects statement latencies, query cache usage and
+// connection pool statistics, and exposes them in the Prometheus text
+// exposition format without depending on a Prometheus client.
+//
+// Example:
+//
+//	c := metrics.NewCollector()
+//	e := engine.New(conn, engine.WithMetrics(c, "main"))
+//	http.Handle("/metrics", c)
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Konsultn-Engineering/enorm/cache"
+	"github.com/Konsultn-Engineering/enorm/connector"
+)
+
+// DefaultBuckets are the latency histogram bucket bounds, in seconds.
+var DefaultBuckets = []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Collector aggregates metrics from any number of engines and connections.
+// It is safe for concurrent use and implements http.Handler.
+type Collector struct {
+	buckets []float64
+
+	mu     sync.RWMutex
+	ops    map[string]*histogram // By operation
+	caches map[string]cache.QueryCache
+	pools  map[string]connector.Connection
+}
+
+// NewCollector returns a collector using buckets (in seconds, ascending) for
+// latency histograms, or DefaultBuckets when none are given.
+func NewCollector(buckets ...float64) *Collector {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	return &Collector{
+		buckets: slices.Clone(buckets),
+		ops:     make(map[string]*histogram),
+		caches:  make(map[string]cache.QueryCache),
+		pools:   make(map[string]connector.Connection),
+	}
+}
+
+// ObserveStatement records a statement of operation op (SELECT, INSERT...)
+// that took d and failed with err, if not nil.
+func (c *Collector) ObserveStatement(op string, d time.Duration, err error) {
+	c.mu.RLock()
+	h := c.ops[op]
+	c.mu.RUnlock()
+
+	if h == nil {
+		c.mu.Lock()
+		if h = c.ops[op]; h == nil {
+			h = newHistogram(len(c.buckets))
+			c.ops[op] = h
+		}
+		c.mu.Unlock()
+	}
+	h.observe(c.buckets, d, err)
+}
+
+// AddQueryCache reports the hits, misses and size of qc, labelled cache=name.
+// Adding another cache under the same name replaces it.
+func (c *Collector) AddQueryCache(name string, qc cache.QueryCache) {
+	c.mu.Lock()
+	c.caches[name] = qc
+	c.mu.Unlock()
+}
+
+// AddConnection reports the pool statistics of conn, labelled pool=name. The
+// members of a cluster are reported separately with role=primary and
+// role=replica_N. Adding another connection under the same name replaces it.
+func (c *Collector) AddConnection(name string, conn connector.Connection) {
+	c.mu.Lock()
+	c.pools[name] = conn
+	c.mu.Unlock()
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format.
+func (c *Collector) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = c.WritePrometheus(w)
+}
+
+// WritePrometheus writes the metrics to w in the Prometheus text exposition
+// format.
+func (c *Collector) WritePrometheus(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	p := &printer{w: bw}
+
+	c.mu.RLock()
+	ops := sortedKeys(c.ops)
+	hists := make([]*histogram, len(ops))
+	for i, op := range ops {
+		hists[i] = c.ops[op]
+	}
+	caches := sortedKeys(c.caches)
+	stats := make([]cache.QueryCacheStats, len(caches))
+	for i, name := range caches {
+		stats[i] = c.caches[name].Stats()
+	}
+	var members []poolMember
+	for _, name := range sortedKeys(c.pools) {
+		members = appendMembers(members, name, c.pools[name])
+	}
+	c.mu.RUnlock()
+
+	if len(ops) > 0 {
+		p.family("enorm_statement_duration_seconds", "histogram", "Time spent executing statements, by operation.")
+		for i, op := range ops {
+			hists[i].write(p, "enorm_statement_duration_seconds", c.buckets, label{"op", op})
+		}
+		p.family("enorm_statement_errors_total", "counter", "Statements that returned an error, by operation.")
+		for i, op := range ops {
+			p.sample("enorm_statement_errors_total", float64(hists[i].errors.Load()), label{"op", op})
+		}
+	}
+
+	if len(caches) > 0 {
+		p.family("enorm_query_cache_hits_total", "counter", "Rendered statements served from the query cache.")
+		for i, name := range caches {
+			p.sample("enorm_query_cache_hits_total", float64(stats[i].Hits), label{"cache", name})
+		}
+		p.family("enorm_query_cache_misses_total", "counter", "Statements rendered because they were not in the query cache.")
+		for i, name := range caches {
+			p.sample("enorm_query_cache_misses_total", float64(stats[i].Misses), label{"cache", name})
+		}
+		p.family("enorm_query_cache_hit_ratio", "gauge", "Share of query cache lookups that hit.")
+		for i, name := range caches {
+			p.sample("enorm_query_cache_hit_ratio", stats[i].HitRatio(), label{"cache", name})
+		}
+		p.family("enorm_query_cache_entries", "gauge", "Statements held in the query cache.")
+		for i, name := range caches {
+			p.sample("enorm_query_cache_entries", float64(stats[i].Entries), label{"cache", name})
+		}
+	}
+
+	if len(members) > 0 {
+		writePools(p, members)
+	}
+
+	if p.err != nil {
+		return p.err
+	}
+	return bw.Flush()
+}
+
+// histogram is a fixed-bucket latency histogram. counts are per bucket, not
+// cumulative; the last one counts observations above every bound.
+type histogram struct {
+	counts []atomic.Uint64
+	sum    atomic.Int64 // Nanoseconds
+	errors atomic.Uint64
+}
+
+func newHistogram(buckets int) *histogram {
+	return &histogram{counts: make([]atomic.Uint64, buckets+1)}
+}
+
+func (h *histogram) observe(buckets []float64, d time.Duration, err error) {
+	i, _ := slices.BinarySearch(buckets, d.Seconds())
+	h.counts[i].Add(1)
+	h.sum.Add(int64(d))
+	if err != nil {
+		h.errors.Add(1)
+	}
+}
+
+func (h *histogram) write(p *printer, name string, buckets []float64, l label) {
+	var cumulative uint64
+	for i, bound := range buckets {
+		cumulative += h.counts[i].Load()
+		p.sample(name+"_bucket", float64(cumulative), l, label{"le", formatFloat(bound)})
+	}
+	cumulative += h.counts[len(buckets)].Load()
+	p.sample(name+"_bucket", float64(cumulative), l, label{"le", "+Inf"})
+	p.sample(name+"_sum", time.Duration(h.sum.Load()).Seconds(), l)
+	p.sample(name+"_count", float64(cumulative), l)
+}
+
+type poolMember struct {
+	pool, role string
+	stats      connector.ConnectionStats
+}
+
+// appendMembers appends the stats of conn, or of each member of a cluster.
+func appendMembers(members []poolMember, name string, conn connector.Connection) []poolMember {
+	cluster, ok := conn.(connector.ClusterConnection)
+	if !ok {
+		return append(members, poolMember{name, "primary", conn.Stats()})
+	}
+	members = append(members, poolMember{name, "primary", cluster.Primary().Stats()})
+	for i, replica := range cluster.Replicas() {
+		members = append(members, poolMember{name, "replica_" + strconv.Itoa(i), replica.Stats()})
+	}
+	return members
+}
+
+var poolMetrics = []struct {
+	name, typ, help string
+	value           func(s connector.ConnectionStats) float64
+}{
+	{"enorm_pool_open_connections", "gauge", "Open connections, in use or idle.", func(s connector.ConnectionStats) float64 { return float64(s.OpenConnections) }},
+	{"enorm_pool_in_use_connections", "gauge", "Connections currently in use.", func(s connector.ConnectionStats) float64 { return float64(s.InUse) }},
+	{"enorm_pool_idle_connections", "gauge", "Idle connections.", func(s connector.ConnectionStats) float64 { return float64(s.Idle) }},
+	{"enorm_pool_max_connections", "gauge", "Maximum size of the pool.", func(s connector.ConnectionStats) float64 { return float64(s.MaxOpen) }},
+	{"enorm_pool_acquires_total", "counter", "Connections acquired from the pool.", func(s connector.ConnectionStats) float64 { return float64(s.AcquireCount) }},
+	{"enorm_pool_acquire_duration_seconds_total", "counter", "Time spent acquiring connections.", func(s connector.ConnectionStats) float64 { return s.AcquireDuration.Seconds() }},
+	{"enorm_pool_canceled_acquires_total", "counter", "Acquires canceled by their context.", func(s connector.ConnectionStats) float64 { return float64(s.CanceledAcquireCount) }},
+	{"enorm_pool_empty_acquires_total", "counter", "Acquires that waited for a connection.", func(s connector.ConnectionStats) float64 { return float64(s.EmptyAcquireCount) }},
+	{"enorm_pool_new_connections_total", "counter", "Physical connections opened.", func(s connector.ConnectionStats) float64 { return float64(s.NewConnections) }},
+	{"enorm_pool_max_lifetime_destroys_total", "counter", "Connections closed for exceeding their maximum lifetime.", func(s connector.ConnectionStats) float64 { return float64(s.MaxLifetimeDestroyCount) }},
+	{"enorm_pool_max_idle_destroys_total", "counter", "Connections closed for exceeding their maximum idle time.", func(s connector.ConnectionStats) float64 { return float64(s.MaxIdleDestroyCount) }},
+}
+
+func writePools(p *printer, members []poolMember) {
+	for _, m := range poolMetrics {
+		p.family(m.name, m.typ, m.help)
+		for _, member := range members {
+			p.sample(m.name, m.value(member.stats), label{"pool", member.pool}, label{"role", member.role})
+		}
+	}
+}
+
+type label struct{ name, value string }
+
+// printer writes the text exposition format, keeping the first write error.
+type printer struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (p *printer) family(name, typ, help string) {
+	p.printf("# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+func (p *printer) sample(name string, value float64, labels ...label) {
+	var sb strings.Builder
+	sb.WriteString(name)
+	if len(labels) > 0 {
+		sb.WriteByte('{')
+		for i, l := range labels {
+			if i > 0 {
+				sb.WriteByte(',')
+			}
+			sb.WriteString(l.name)
+			sb.WriteString(`="`)
+			sb.WriteString(labelEscaper.Replace(l.value))
+			sb.WriteByte('"')
+		}
+		sb.WriteByte('}')
+	}
+	p.printf("%s %s\n", sb.String(), formatFloat(value))
+}
+
+func (p *printer) printf(format string, args ...any) {
+	if p.err == nil {
+		_, p.err = fmt.Fprintf(p.w, format, args...)
+	}
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatFloat(f float64) string {
+	if math.IsInf(f, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
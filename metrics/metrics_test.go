@@ -0,0 +1,117 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Konsultn-Engineering/enorm/cache"
+	"github.com/Konsultn-Engineering/enorm/connector"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubConn reports fixed pool statistics; other Connection methods are unused.
+type stubConn struct {
+	connector.Connection
+	stats connector.ConnectionStats
+}
+
+func (c *stubConn) Stats() connector.ConnectionStats { return c.stats }
+
+type stubCluster struct {
+	stubConn
+	primary  connector.Connection
+	replicas []connector.Connection
+}
+
+func (c *stubCluster) Primary() connector.Connection              { return c.primary }
+func (c *stubCluster) Replicas() []connector.Connection           { return c.replicas }
+func (c *stubCluster) Read(context.Context) connector.Connection  { return c.replicas[0] }
+func (c *stubCluster) Write(context.Context) connector.Connection { return c.primary }
+
+func TestHistogramBuckets(t *testing.T) {
+	c := NewCollector(0.1, 1)
+	c.ObserveStatement("SELECT", 50*time.Millisecond, nil)
+	c.ObserveStatement("SELECT", 100*time.Millisecond, nil) // Bounds are inclusive
+	c.ObserveStatement("SELECT", 500*time.Millisecond, nil)
+	c.ObserveStatement("SELECT", 2*time.Second, errors.New("timeout"))
+
+	var sb strings.Builder
+	require.NoError(t, c.WritePrometheus(&sb))
+	assert.Equal(t, `# HELP enorm_statement_duration_seconds Time spent executing statements, by operation.
+# TYPE enorm_statement_duration_seconds histogram
+enorm_statement_duration_seconds_bucket{op="SELECT",le="0.1"} 2
+enorm_statement_duration_seconds_bucket{op="SELECT",le="1"} 3
+enorm_statement_duration_seconds_bucket{op="SELECT",le="+Inf"} 4
+enorm_statement_duration_seconds_sum{op="SELECT"} 2.65
+enorm_statement_duration_seconds_count{op="SELECT"} 4
+# HELP enorm_statement_errors_total Statements that returned an error, by operation.
+# TYPE enorm_statement_errors_total counter
+enorm_statement_errors_total{op="SELECT"} 1
+`, sb.String())
+}
+
+func TestDefaultBuckets(t *testing.T) {
+	c := NewCollector()
+	c.ObserveStatement("INSERT", 3*time.Millisecond, nil)
+
+	var sb strings.Builder
+	require.NoError(t, c.WritePrometheus(&sb))
+	out := sb.String()
+	assert.Contains(t, out, `enorm_statement_duration_seconds_bucket{op="INSERT",le="0.0025"} 0`+"\n")
+	assert.Contains(t, out, `enorm_statement_duration_seconds_bucket{op="INSERT",le="0.005"} 1`+"\n")
+	assert.Contains(t, out, `enorm_statement_duration_seconds_bucket{op="INSERT",le="10"} 1`+"\n")
+	assert.Equal(t, len(DefaultBuckets)+1, strings.Count(out, "enorm_statement_duration_seconds_bucket{"))
+}
+
+func TestWritePrometheusCachesAndPools(t *testing.T) {
+	qc := cache.NewQueryCache()
+	qc.Set(1, "SELECT 1", nil, nil, "", "")
+	qc.Get(1)
+	qc.Get(1)
+	qc.Get(2)
+
+	c := NewCollector()
+	c.AddQueryCache(`main "db"`, qc)
+	c.AddConnection("solo", &stubConn{stats: connector.ConnectionStats{OpenConnections: 3, InUse: 1, Idle: 2}})
+	c.AddConnection("cluster", &stubCluster{
+		primary:  &stubConn{stats: connector.ConnectionStats{MaxOpen: 10, AcquireDuration: 1500 * time.Millisecond}},
+		replicas: []connector.Connection{&stubConn{stats: connector.ConnectionStats{MaxOpen: 5}}},
+	})
+
+	var sb strings.Builder
+	require.NoError(t, c.WritePrometheus(&sb))
+	out := sb.String()
+
+	// No statements observed: no statement families
+	assert.NotContains(t, out, "enorm_statement_")
+	for _, line := range []string{
+		"# TYPE enorm_query_cache_hits_total counter",
+		`enorm_query_cache_hits_total{cache="main \"db\""} 2`,
+		`enorm_query_cache_misses_total{cache="main \"db\""} 1`,
+		`enorm_query_cache_hit_ratio{cache="main \"db\""} 0.6666666666666666`,
+		`enorm_query_cache_entries{cache="main \"db\""} 1`,
+		"# TYPE enorm_pool_open_connections gauge",
+		`enorm_pool_open_connections{pool="solo",role="primary"} 3`,
+		`enorm_pool_in_use_connections{pool="solo",role="primary"} 1`,
+		`enorm_pool_idle_connections{pool="solo",role="primary"} 2`,
+		`enorm_pool_max_connections{pool="cluster",role="primary"} 10`,
+		`enorm_pool_max_connections{pool="cluster",role="replica_0"} 5`,
+		`enorm_pool_acquire_duration_seconds_total{pool="cluster",role="primary"} 1.5`,
+	} {
+		assert.Contains(t, out, line+"\n")
+	}
+
+	// Pools are sorted by name within each family
+	assert.Less(t, strings.Index(out, `enorm_pool_open_connections{pool="cluster"`),
+		strings.Index(out, `enorm_pool_open_connections{pool="solo"`))
+}
+
+func TestWritePrometheusEmpty(t *testing.T) {
+	var sb strings.Builder
+	require.NoError(t, NewCollector().WritePrometheus(&sb))
+	assert.Empty(t, sb.String())
+}
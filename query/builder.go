@@ -79,18 +79,52 @@ func (b *Builder) Release() {
 	builderPool.Put(b)
 }
 
+// Reset discards the statement built so far (conditions, ordering, limits,
+// subqueries and errors) so the builder can start a new query.
+func (b *Builder) Reset() {
+	child := b.firstChild
+	for child != nil {
+		next := child.nextSibling
+		child.Release()
+		child = next
+	}
+	b.firstChild = nil
+
+	if b.stmt != nil {
+		b.stmt.Release()
+	}
+	b.stmt = ast.NewSelectStmt()
+	if b.tableName != "" {
+		b.stmt.From = ast.NewTable(b.schema, b.tableName, "")
+	}
+	b.paramCount = 0
+	b.errors = b.errors[:0]
+}
+
 // Core accessors
 func (b *Builder) TableName() string {
 	return b.tableName
 }
 
+// Schema returns the schema that qualifies the tables of this builder.
+func (b *Builder) Schema() string {
+	return b.schema
+}
+
+// SetSchema qualifies the tables added from now on (FROM, joins and
+// subqueries) with schema. An empty schema leaves them unqualified.
+func (b *Builder) SetSchema(schema string) *Builder {
+	b.schema = schema
+	return b
+}
+
 func (b *Builder) Visitor() *visitor.SQLVisitor {
 	return b.visitor
 }
 
 func (b *Builder) GetStatement(name, table string, cols []string) ast.Node {
 	if table != "" {
-		b.stmt.From = ast.NewTable("", table, "")
+		b.stmt.From = ast.NewTable(b.schema, table, "")
 	}
 
 	if len(b.stmt.Columns) == 0 {
@@ -102,6 +136,18 @@ func (b *Builder) GetStatement(name, table string, cols []string) ast.Node {
 	return b.stmt
 }
 
+// Statement returns the SELECT statement being built. It stays owned by the
+// builder and is released by Reset or Release.
+func (b *Builder) Statement() *ast.SelectStmt {
+	return b.stmt
+}
+
+// WhereClause returns the WHERE clause accumulated so far, or nil.
+// The clause stays owned by the builder and is released by Reset or Release.
+func (b *Builder) WhereClause() *ast.WhereClause {
+	return b.stmt.Where
+}
+
 // Error handling
 func (b *Builder) AddError(err error) {
 	if err != nil {
@@ -133,6 +179,49 @@ func (b *Builder) OrWhere(column string, operator string, value any) *Builder {
 	return b.whereWithOperator(column, operator, value, ast.OpOr)
 }
 
+// WhereExpr adds a prebuilt condition node (e.g. a row-value comparison) with AND.
+func (b *Builder) WhereExpr(condition ast.Node) *Builder {
+	b.stmt.AddWhereCondition(condition, ast.OpAnd)
+	return b
+}
+
+// Scope ANDs condition onto the whole WHERE clause, grouping existing OR
+// conditions first. Used for predicates that must always apply (e.g. soft delete).
+func (b *Builder) Scope(condition ast.Node) *Builder {
+	b.stmt.AddScopeCondition(condition)
+	return b
+}
+
+// ApplyScope runs fn against this builder and ANDs the WHERE conditions it
+// adds onto the existing clause as one group, rewriting
+//
+//	WHERE a = 1 OR b = 2   +   scope: c = 3 OR d = 4
+//
+// into WHERE (a = 1 OR b = 2) AND (c = 3 OR d = 4). Other clauses the scope
+// sets (ordering, limits, joins) apply as usual.
+func (b *Builder) ApplyScope(fn ScopeFunc) *Builder {
+	before := b.stmt.Where
+	b.stmt.Where = nil // Collect the scope's conditions separately
+	fn(b)
+	added := b.stmt.Where
+	b.stmt.Where = before
+
+	if added == nil || added.First == nil {
+		return b
+	}
+
+	var condition ast.Node
+	if added.First == added.Tail {
+		condition = added.First.Condition
+		added.First.Condition = nil
+		added.Release()
+	} else {
+		condition = &ast.GroupedExpr{Expr: added}
+	}
+	b.stmt.AddScopeCondition(condition)
+	return b
+}
+
 // Core ORDER BY method
 func (b *Builder) OrderBy(columns []string, desc bool) *Builder {
 	b.stmt.AddOrderByClause(b.tableName, desc, columns...)
@@ -189,7 +278,7 @@ func (b *Builder) Distinct() *Builder {
 
 // Core JOIN methods
 func (b *Builder) Join(joinType ast.JoinType, table string, leftCol string, operator string, rightCol string) *Builder {
-	b.stmt.AddJoinClause(joinType, "", table, "")
+	b.stmt.AddJoinClause(joinType, b.schema, table, "")
 
 	if len(b.stmt.Joins) > 0 {
 		lastJoin := b.stmt.Joins[len(b.stmt.Joins)-1]
@@ -211,7 +300,7 @@ func (b *Builder) Join(joinType ast.JoinType, table string, leftCol string, oper
 
 // Core subquery methods
 func (b *Builder) WhereSubquery(column string, operator string, subqueryFn func(*Builder)) *Builder {
-	subBuilder := NewBuilder("", "", b.visitor)
+	subBuilder := NewBuilder(b.schema, "", b.visitor)
 	b.addChild(subBuilder)
 	subqueryFn(subBuilder)
 
@@ -226,7 +315,7 @@ func (b *Builder) WhereSubquery(column string, operator string, subqueryFn func(
 }
 
 func (b *Builder) WhereExists(subqueryFn func(*Builder)) *Builder {
-	subBuilder := NewBuilder("", "", b.visitor)
+	subBuilder := NewBuilder(b.schema, "", b.visitor)
 	b.addChild(subBuilder)
 	subqueryFn(subBuilder)
 
@@ -243,7 +332,7 @@ func (b *Builder) WhereExists(subqueryFn func(*Builder)) *Builder {
 // Build method - using existing visitor pattern
 func (b *Builder) Build(table string, cols []string) (string, []interface{}, error) {
 	if b.stmt.From == nil {
-		b.stmt.From = ast.NewTable("", table, "")
+		b.stmt.From = ast.NewTable(b.schema, table, "")
 	}
 
 	if len(b.stmt.Columns) == 0 {
@@ -0,0 +1,101 @@
+package query
+
+import (
+	"reflect"
+	"sync"
+)
+
+// ScopeFunc adds reusable conditions to a query. Conditions added by a scope
+// are ANDed onto the query as a single group, so an OR inside a scope cannot
+// widen the query (see Builder.ApplyScope).
+type ScopeFunc func(b *Builder)
+
+// NamedScope is a scope registered for an entity type.
+type NamedScope struct {
+	Name    string
+	Fn      ScopeFunc
+	Default bool // Applied to every query for the type
+}
+
+// scopes holds registered scopes per struct type, in registration order.
+var scopes = struct {
+	mu     sync.RWMutex
+	byType map[reflect.Type][]NamedScope
+}{
+	byType: make(map[reflect.Type][]NamedScope),
+}
+
+// RegisterScope registers a named scope for entity type T, applied on request.
+//
+// Example:
+//
+//	query.RegisterScope[Post]("published", func(b *query.Builder) {
+//	    b.Where("published", ast.OpEqual, true)
+//	})
+//
+//	e.Scopes("published").Find(&posts)
+func RegisterScope[T any](name string, fn ScopeFunc) {
+	registerScope(typeOf[T](), NamedScope{Name: name, Fn: fn})
+}
+
+// RegisterDefaultScope registers a scope applied to every query for entity
+// type T unless excluded with WithoutScope.
+//
+// Example:
+//
+//	query.RegisterDefaultScope[Post]("visible", func(b *query.Builder) {
+//	    b.Where("hidden", ast.OpEqual, false)
+//	})
+func RegisterDefaultScope[T any](name string, fn ScopeFunc) {
+	registerScope(typeOf[T](), NamedScope{Name: name, Fn: fn, Default: true})
+}
+
+// LookupScope returns the scope registered under name for struct type t.
+func LookupScope(t reflect.Type, name string) (NamedScope, bool) {
+	scopes.mu.RLock()
+	defer scopes.mu.RUnlock()
+	for _, s := range scopes.byType[structType(t)] {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return NamedScope{}, false
+}
+
+// DefaultScopes returns the default scopes registered for struct type t.
+func DefaultScopes(t reflect.Type) []NamedScope {
+	scopes.mu.RLock()
+	defer scopes.mu.RUnlock()
+	var defaults []NamedScope
+	for _, s := range scopes.byType[structType(t)] {
+		if s.Default {
+			defaults = append(defaults, s)
+		}
+	}
+	return defaults
+}
+
+// registerScope adds s for t, replacing a scope of the same name.
+func registerScope(t reflect.Type, s NamedScope) {
+	scopes.mu.Lock()
+	defer scopes.mu.Unlock()
+	list := scopes.byType[t]
+	for i := range list {
+		if list[i].Name == s.Name {
+			list[i] = s
+			return
+		}
+	}
+	scopes.byType[t] = append(list, s)
+}
+
+func typeOf[T any]() reflect.Type {
+	return structType(reflect.TypeOf((*T)(nil)).Elem())
+}
+
+func structType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		return t.Elem()
+	}
+	return t
+}
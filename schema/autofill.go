@@ -0,0 +1,263 @@
+package schema
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+	"unsafe"
+)
+
+var nullTimeType = reflect.TypeOf(sql.NullTime{})
+
+// PrepareInsert fills the values a new row needs before it is written:
+// zero-valued fields with an ID generator receive a generated ID,
+// zero-valued auto_now_add fields and all auto_now fields receive the
+// Context's current time.
+func (ctx *Context) PrepareInsert(meta *EntityMeta, structPtr unsafe.Pointer) error {
+	var now time.Time
+	for _, fm := range meta.Fields {
+		if fm.Generator != nil && fm.IsZeroIn(structPtr) {
+			if err := fm.generate(structPtr); err != nil {
+				return err
+			}
+		}
+
+		if fm.Tag.AutoNow || (fm.Tag.AutoNowAdd && fm.IsZeroIn(structPtr)) {
+			if now.IsZero() {
+				now = ctx.Now()
+			}
+			fm.SetTimestamp(structPtr, now)
+		}
+	}
+	return nil
+}
+
+// PrepareUpdate stamps every auto_now field with the Context's current time
+// and returns those fields, so callers can add them to the SET list.
+func (ctx *Context) PrepareUpdate(meta *EntityMeta, structPtr unsafe.Pointer) []*FieldMeta {
+	fields := meta.AutoNowFields()
+	if len(fields) == 0 {
+		return nil
+	}
+	now := ctx.Now()
+	for _, fm := range fields {
+		fm.SetTimestamp(structPtr, now)
+	}
+	return fields
+}
+
+// AutoNowFields returns the fields tagged auto_now, in declaration order.
+func (m *EntityMeta) AutoNowFields() []*FieldMeta {
+	var fields []*FieldMeta
+	for _, fm := range m.Fields {
+		if fm.Tag.AutoNow {
+			fields = append(fields, fm)
+		}
+	}
+	return fields
+}
+
+// IsTimestampType reports whether auto_now / auto_now_add can manage a field
+// of type t: time.Time, *time.Time, sql.NullTime or an integer holding Unix seconds.
+func IsTimestampType(t reflect.Type) bool {
+	switch t {
+	case timeType, reflect.PointerTo(timeType), nullTimeType:
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// IsNullableTimeType reports whether t can hold a NULL timestamp:
+// *time.Time or sql.NullTime.
+func IsNullableTimeType(t reflect.Type) bool {
+	return t == reflect.PointerTo(timeType) || t == nullTimeType
+}
+
+// TimestampValue returns now in this field's representation (see IsTimestampType).
+func (fm *FieldMeta) TimestampValue(now time.Time) any {
+	switch fm.Type {
+	case timeType:
+		return now
+	case reflect.PointerTo(timeType):
+		return &now
+	case nullTimeType:
+		return sql.NullTime{Time: now, Valid: true}
+	}
+	return reflect.ValueOf(now.Unix()).Convert(fm.Type).Interface()
+}
+
+// SetTimestamp stores now in this field of the struct at structPtr.
+func (fm *FieldMeta) SetTimestamp(structPtr unsafe.Pointer, now time.Time) {
+	field := reflect.NewAt(fm.Type, unsafe.Add(structPtr, fm.Offset)).Elem()
+	field.Set(reflect.ValueOf(fm.TimestampValue(now)))
+}
+
+// StampTenant stores tenant in the Tenant field of the struct at structPtr,
+// converting it like a generated ID (see assignGenerated). A zero field is
+// filled in; a field that already names a different tenant is an error, so a
+// row can never be written on behalf of another tenant.
+func (m *EntityMeta) StampTenant(structPtr unsafe.Pointer, tenant any) error {
+	fm := m.Tenant
+	want := reflect.New(fm.Type).Elem()
+	if err := assignGenerated(want, tenant); err != nil {
+		return fmt.Errorf("tenant for field %s: %w", fm.Name, err)
+	}
+
+	field := reflect.NewAt(fm.Type, unsafe.Add(structPtr, fm.Offset)).Elem()
+	if field.IsZero() {
+		field.Set(want)
+		return nil
+	}
+	if !reflect.DeepEqual(field.Interface(), want.Interface()) {
+		return fmt.Errorf("%s belongs to tenant %v, not %v", m.Name, field.Interface(), tenant)
+	}
+	return nil
+}
+
+// generate stores a freshly generated ID in this field, converting it to the
+// field's type (see assignGenerated).
+func (fm *FieldMeta) generate(structPtr unsafe.Pointer) error {
+	id, err := fm.Generator.Generate()
+	if err != nil {
+		return fmt.Errorf("generate %s for %s: %w", fm.Generator.Type(), fm.Name, err)
+	}
+
+	field := reflect.NewAt(fm.Type, unsafe.Add(structPtr, fm.Offset)).Elem()
+	if err := assignGenerated(field, id); err != nil {
+		return fmt.Errorf("generator %s for field %s: %w", fm.Generator.Type(), fm.Name, err)
+	}
+	return nil
+}
+
+// assignGenerated stores a generated ID in field. Supported conversions:
+//
+//   - uuid.UUID, ulid.ULID ([16]byte): string (canonical text form), any
+//     [16]byte-based type, []byte
+//   - integers (snowflake): any integer type that can hold the value, string
+//   - strings (nanoid): any string type, []byte
+//
+// Pointer fields receive a newly allocated value, and fields implementing
+// sql.Scanner (e.g., sql.NullString) are scanned from the text or integer form.
+func assignGenerated(field reflect.Value, id any) error {
+	v := reflect.ValueOf(id)
+	t := field.Type()
+
+	if v.Type().AssignableTo(t) {
+		field.Set(v)
+		return nil
+	}
+
+	if t.Kind() == reflect.Ptr {
+		elem := reflect.New(t.Elem())
+		if err := assignGenerated(elem.Elem(), id); err != nil {
+			return err
+		}
+		field.Set(elem)
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Array:
+		if v.Type().Elem().Kind() != reflect.Uint8 {
+			break
+		}
+		switch {
+		case t.Kind() == reflect.String:
+			if s, ok := id.(fmt.Stringer); ok {
+				field.SetString(s.String())
+				return nil
+			}
+		case t.Kind() == reflect.Array && t.Len() == v.Len() && t.Elem().Kind() == reflect.Uint8:
+			reflect.Copy(field, v)
+			return nil
+		case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+			b := reflect.MakeSlice(t, v.Len(), v.Len())
+			reflect.Copy(b, v)
+			field.Set(b)
+			return nil
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n := v.Int()
+		switch t.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if field.OverflowInt(n) {
+				return fmt.Errorf("generated ID %d overflows %s", n, t)
+			}
+			field.SetInt(n)
+			return nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if n < 0 || field.OverflowUint(uint64(n)) {
+				return fmt.Errorf("generated ID %d overflows %s", n, t)
+			}
+			field.SetUint(uint64(n))
+			return nil
+		case reflect.String:
+			field.SetString(strconv.FormatInt(n, 10))
+			return nil
+		}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n := v.Uint()
+		switch t.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if n > math.MaxInt64 || field.OverflowInt(int64(n)) {
+				return fmt.Errorf("generated ID %d overflows %s", n, t)
+			}
+			field.SetInt(int64(n))
+			return nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if field.OverflowUint(n) {
+				return fmt.Errorf("generated ID %d overflows %s", n, t)
+			}
+			field.SetUint(n)
+			return nil
+		case reflect.String:
+			field.SetString(strconv.FormatUint(n, 10))
+			return nil
+		}
+
+	case reflect.String:
+		switch {
+		case t.Kind() == reflect.String:
+			field.SetString(v.String())
+			return nil
+		case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+			field.SetBytes([]byte(v.String()))
+			return nil
+		}
+	}
+
+	if scanner, ok := field.Addr().Interface().(sql.Scanner); ok {
+		if err := scanner.Scan(driverForm(v)); err != nil {
+			return fmt.Errorf("cannot scan generated %T into %s: %w", id, t, err)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("cannot assign generated %T to field of type %s", id, t)
+}
+
+// driverForm converts a generated ID to a value accepted by sql.Scanner
+// implementations: text for byte arrays and strings, int64 for integers.
+func driverForm(v reflect.Value) any {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(v.Uint())
+	case reflect.String:
+		return v.String()
+	}
+	if s, ok := v.Interface().(fmt.Stringer); ok {
+		return s.String()
+	}
+	return v.Interface()
+}
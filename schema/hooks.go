@@ -0,0 +1,153 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+	"math/bits"
+	"reflect"
+	"unsafe"
+)
+
+// Lifecycle hooks are implemented by entities on the pointer receiver and run
+// by the engine around each write or read. A hook returning an error aborts
+// the operation; writes with hooks run in a transaction, so an error from an
+// After hook rolls the write back.
+//
+// Example:
+//
+//	func (u *User) BeforeCreate(ctx context.Context) error {
+//	    u.Email = strings.ToLower(u.Email)
+//	    return nil
+//	}
+type (
+	// BeforeCreateHook runs before an entity is inserted, ahead of ID
+	// generation, timestamps and validation.
+	BeforeCreateHook interface {
+		BeforeCreate(ctx context.Context) error
+	}
+	// AfterCreateHook runs after an entity is inserted and generated keys are
+	// read back.
+	AfterCreateHook interface {
+		AfterCreate(ctx context.Context) error
+	}
+	// BeforeUpdateHook runs before an entity is updated, ahead of timestamps
+	// and validation.
+	BeforeUpdateHook interface {
+		BeforeUpdate(ctx context.Context) error
+	}
+	// AfterUpdateHook runs after an entity is updated.
+	AfterUpdateHook interface {
+		AfterUpdate(ctx context.Context) error
+	}
+	// BeforeDeleteHook runs before an entity is deleted (or soft-deleted).
+	BeforeDeleteHook interface {
+		BeforeDelete(ctx context.Context) error
+	}
+	// AfterDeleteHook runs after an entity is deleted (or soft-deleted).
+	AfterDeleteHook interface {
+		AfterDelete(ctx context.Context) error
+	}
+	// AfterFindHook runs after an entity is loaded, once its preloads are done.
+	AfterFindHook interface {
+		AfterFind(ctx context.Context) error
+	}
+)
+
+// Hook identifies a lifecycle hook. EntityMeta.Hooks holds the set an entity
+// implements.
+type Hook uint8
+
+const (
+	HookBeforeCreate Hook = 1 << iota
+	HookAfterCreate
+	HookBeforeUpdate
+	HookAfterUpdate
+	HookBeforeDelete
+	HookAfterDelete
+	HookAfterFind
+)
+
+// hookCount is the number of lifecycle hooks.
+const hookCount = 7
+
+// hookFunc calls a resolved hook on the struct at structPtr.
+type hookFunc func(structPtr unsafe.Pointer, ctx context.Context) error
+
+var hookTypes = [hookCount]struct {
+	hook Hook
+	name string
+	typ  reflect.Type
+	bind func(reflect.Type) hookFunc
+}{
+	{HookBeforeCreate, "BeforeCreate", reflect.TypeOf((*BeforeCreateHook)(nil)).Elem(),
+		func(t reflect.Type) hookFunc { return bindHook(t, BeforeCreateHook.BeforeCreate) }},
+	{HookAfterCreate, "AfterCreate", reflect.TypeOf((*AfterCreateHook)(nil)).Elem(),
+		func(t reflect.Type) hookFunc { return bindHook(t, AfterCreateHook.AfterCreate) }},
+	{HookBeforeUpdate, "BeforeUpdate", reflect.TypeOf((*BeforeUpdateHook)(nil)).Elem(),
+		func(t reflect.Type) hookFunc { return bindHook(t, BeforeUpdateHook.BeforeUpdate) }},
+	{HookAfterUpdate, "AfterUpdate", reflect.TypeOf((*AfterUpdateHook)(nil)).Elem(),
+		func(t reflect.Type) hookFunc { return bindHook(t, AfterUpdateHook.AfterUpdate) }},
+	{HookBeforeDelete, "BeforeDelete", reflect.TypeOf((*BeforeDeleteHook)(nil)).Elem(),
+		func(t reflect.Type) hookFunc { return bindHook(t, BeforeDeleteHook.BeforeDelete) }},
+	{HookAfterDelete, "AfterDelete", reflect.TypeOf((*AfterDeleteHook)(nil)).Elem(),
+		func(t reflect.Type) hookFunc { return bindHook(t, AfterDeleteHook.AfterDelete) }},
+	{HookAfterFind, "AfterFind", reflect.TypeOf((*AfterFindHook)(nil)).Elem(),
+		func(t reflect.Type) hookFunc { return bindHook(t, AfterFindHook.AfterFind) }},
+}
+
+// iface mirrors the runtime layout of a non-empty interface value.
+type iface struct {
+	tab  unsafe.Pointer
+	data unsafe.Pointer
+}
+
+// bindHook resolves method for *t once. The returned function pairs the
+// interface table captured here with the struct pointer, so calls need
+// neither reflection nor a type assertion.
+func bindHook[I any](t reflect.Type, method func(I, context.Context) error) hookFunc {
+	proto := reflect.New(t).Interface().(I)
+	tab := (*iface)(unsafe.Pointer(&proto)).tab
+	return func(structPtr unsafe.Pointer, ctx context.Context) error {
+		var hook I
+		*(*iface)(unsafe.Pointer(&hook)) = iface{tab: tab, data: structPtr}
+		return method(hook, ctx)
+	}
+}
+
+func (h Hook) String() string {
+	for _, ht := range hookTypes {
+		if ht.hook == h {
+			return ht.name
+		}
+	}
+	return fmt.Sprintf("Hook(%d)", uint8(h))
+}
+
+// detectHooks records the hooks implemented by *t and resolves each of them.
+func (m *EntityMeta) detectHooks(t reflect.Type) {
+	pt := reflect.PointerTo(t)
+	for i, ht := range hookTypes {
+		if pt.Implements(ht.typ) {
+			m.Hooks |= ht.hook
+			m.hookFuncs[i] = ht.bind(t)
+		}
+	}
+}
+
+// HasHook reports whether the entity implements any of hooks.
+func (m *EntityMeta) HasHook(hooks Hook) bool {
+	return m.Hooks&hooks != 0
+}
+
+// RunHook calls hook on the struct at structPtr. Entities that do not
+// implement it return nil.
+func (m *EntityMeta) RunHook(ctx context.Context, hook Hook, structPtr unsafe.Pointer) error {
+	if m.Hooks&hook == 0 {
+		return nil
+	}
+
+	if err := m.hookFuncs[bits.TrailingZeros8(uint8(hook))](structPtr, ctx); err != nil {
+		return fmt.Errorf("%s.%s: %w", m.Name, hook, err)
+	}
+	return nil
+}
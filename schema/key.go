@@ -0,0 +1,87 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+// HasCompositeKey returns true if the primary key spans more than one column.
+func (m *EntityMeta) HasCompositeKey() bool {
+	return len(m.PrimaryKey) > 1
+}
+
+// PrimaryKeyColumns returns the primary key column names in key order.
+func (m *EntityMeta) PrimaryKeyColumns() []string {
+	cols := make([]string, len(m.PrimaryKey))
+	for i, fm := range m.PrimaryKey {
+		cols[i] = fm.DBName
+	}
+	return cols
+}
+
+// IsPrimaryKey returns true if column is part of the primary key.
+func (m *EntityMeta) IsPrimaryKey(column string) bool {
+	for _, fm := range m.PrimaryKey {
+		if fm.DBName == column {
+			return true
+		}
+	}
+	return false
+}
+
+// KeyValues returns the primary key values of the struct at structPtr in key order.
+// Returns an error if the entity has no primary key or every key field is zero.
+func (m *EntityMeta) KeyValues(structPtr unsafe.Pointer) ([]any, error) {
+	if len(m.PrimaryKey) == 0 {
+		return nil, fmt.Errorf("%s has no primary key", m.Name)
+	}
+
+	values := make([]any, len(m.PrimaryKey))
+	allZero := true
+	for i, fm := range m.PrimaryKey {
+		values[i] = fm.ValueOf(structPtr)
+		if !fm.IsZeroIn(structPtr) {
+			allZero = false
+		}
+	}
+
+	if allZero {
+		return nil, fmt.Errorf("%s primary key %v is not set", m.Name, m.PrimaryKeyColumns())
+	}
+	return values, nil
+}
+
+// ResolveKey normalises the arguments of a primary key lookup into key-ordered values.
+// Accepted forms:
+//   - one value per key column: ResolveKey(orderID, lineNo)
+//   - a single []any tuple:      ResolveKey([]any{orderID, lineNo})
+//   - an entity (or pointer) of this type, whose key fields are read
+func (m *EntityMeta) ResolveKey(key ...any) ([]any, error) {
+	if len(m.PrimaryKey) == 0 {
+		return nil, fmt.Errorf("%s has no primary key", m.Name)
+	}
+
+	if len(key) == 1 {
+		switch k := key[0].(type) {
+		case []any:
+			key = k
+		default:
+			rv := reflect.ValueOf(k)
+			if rv.Kind() == reflect.Ptr && !rv.IsNil() && rv.Elem().Type() == m.Type {
+				return m.KeyValues(rv.UnsafePointer())
+			}
+			if rv.IsValid() && rv.Type() == m.Type {
+				ptr := reflect.New(m.Type)
+				ptr.Elem().Set(rv)
+				return m.KeyValues(ptr.UnsafePointer())
+			}
+		}
+	}
+
+	if len(key) != len(m.PrimaryKey) {
+		return nil, fmt.Errorf("%s primary key %v expects %d values, got %d",
+			m.Name, m.PrimaryKeyColumns(), len(m.PrimaryKey), len(key))
+	}
+	return key, nil
+}
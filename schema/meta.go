@@ -50,6 +50,7 @@ func (ctx *Context) buildMeta(t reflect.Type) (*EntityMeta, error) {
 		FieldMap:     make(map[string]*FieldMeta, exportedCount),
 		ColumnMap:    make(map[string]*FieldMeta, exportedCount),
 		AliasMapping: make(map[string]string, exportedCount),
+		Relations:    make(map[string]*RelationMeta),
 	}
 
 	// Determine table name
@@ -82,6 +83,20 @@ func (ctx *Context) buildMeta(t reflect.Type) (*EntityMeta, error) {
 			continue
 		}
 
+		// Relation fields are loaded separately and never mapped to columns
+		if parsedTag.IsRelation() {
+			rel, err := ctx.buildRelation(f, parsedTag)
+			if err != nil {
+				return nil, err
+			}
+			meta.Relations[f.Name] = rel
+			continue
+		}
+
+		if (parsedTag.AutoNow || parsedTag.AutoNowAdd) && !IsTimestampType(f.Type) {
+			return nil, fmt.Errorf("field %s: auto_now/auto_now_add requires time.Time, *time.Time, sql.NullTime or a Unix-seconds integer, got %s", f.Name, f.Type)
+		}
+
 		// Create field metadata
 		fm := &FieldMeta{
 			Name:       f.Name,
@@ -110,9 +125,47 @@ func (ctx *Context) buildMeta(t reflect.Type) (*EntityMeta, error) {
 		meta.FieldMap[f.Name] = fm
 		meta.ColumnMap[fm.DBName] = fm
 		meta.AliasMapping[fm.DBName] = f.Name
+
+		if parsedTag.Primary {
+			meta.PrimaryKey = append(meta.PrimaryKey, fm)
+		}
+
+		if parsedTag.SoftDelete {
+			if !IsNullableTimeType(f.Type) {
+				return nil, fmt.Errorf("field %s: soft_delete requires *time.Time or sql.NullTime, got %s", f.Name, f.Type)
+			}
+			if meta.SoftDelete != nil {
+				return nil, fmt.Errorf("field %s: %s already has soft_delete field %s", f.Name, meta.Name, meta.SoftDelete.Name)
+			}
+			meta.SoftDelete = fm
+		}
+
+		if parsedTag.Tenant {
+			if meta.Tenant != nil {
+				return nil, fmt.Errorf("field %s: %s already has tenant field %s", f.Name, meta.Name, meta.Tenant.Name)
+			}
+			meta.Tenant = fm
+		}
 	}
 	meta.Columns = columnSlice
 
+	// Fall back to the deleted_at convention when no field is tagged soft_delete
+	if meta.SoftDelete == nil {
+		if fm := meta.ColumnMap["deleted_at"]; fm != nil && IsNullableTimeType(fm.Type) {
+			meta.SoftDelete = fm
+		}
+	}
+
+	// Fall back to the conventional "id" column when no field is tagged primary
+	if len(meta.PrimaryKey) == 0 {
+		if fm := meta.ColumnMap["id"]; fm != nil {
+			meta.PrimaryKey = []*FieldMeta{fm}
+		}
+	}
+
+	meta.detectHooks(t)
+	ctx.registerSensitive(meta)
+
 	// Check for custom scanner
 	if fn := getRegisteredScanner(t); fn != nil {
 		meta.ScannerFn = fn
@@ -0,0 +1,157 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Relation kinds recognised in struct tags.
+const (
+	RelationHasMany   = "has_many"
+	RelationHasOne    = "has_one"
+	RelationBelongsTo = "belongs_to"
+)
+
+// RelationMeta describes an association declared on a struct field.
+// Relation fields are never mapped to columns; they are populated by preloading.
+//
+// Polymorphic associations use a type/id column pair on the child table:
+//
+//	type Comment struct {
+//	    ID              uint64
+//	    CommentableType string
+//	    CommentableID   uint64
+//	    Commentable     any `db:"belongs_to;polymorphic:Commentable"`
+//	}
+//
+//	type Post struct {
+//	    ID       uint64
+//	    Comments []*Comment `db:"has_many;polymorphic:Commentable"`
+//	}
+type RelationMeta struct {
+	Name   string       // Go field name (e.g., "Comments")
+	Kind   string       // RelationHasMany, RelationHasOne or RelationBelongsTo
+	Type   reflect.Type // Go field type (e.g., []*Comment)
+	Target reflect.Type // Related struct type; nil for polymorphic belongs_to
+	Index  []int        // Field index path for reflect.Value.FieldByIndex()
+
+	// Polymorphic configuration
+	Polymorphic string // Association name (e.g., "Commentable")
+	TypeColumn  string // Discriminator column on the child table (e.g., "commentable_type")
+	IDColumn    string // Foreign key column on the child table (e.g., "commentable_id")
+}
+
+// IsPolymorphic returns true if the relation uses a type/id column pair.
+func (r *RelationMeta) IsPolymorphic() bool {
+	return r.Polymorphic != ""
+}
+
+// buildRelation creates relation metadata for a field tagged with a relation kind.
+func (ctx *Context) buildRelation(f reflect.StructField, tag *ParsedTag) (*RelationMeta, error) {
+	rel := &RelationMeta{
+		Name:        f.Name,
+		Kind:        tag.Relation,
+		Type:        f.Type,
+		Index:       f.Index,
+		Polymorphic: tag.Polymorphic,
+	}
+
+	if rel.IsPolymorphic() {
+		rel.TypeColumn = ctx.namingStrategy.ColumnName(tag.Polymorphic + "Type")
+		rel.IDColumn = ctx.namingStrategy.ColumnName(tag.Polymorphic + "ID")
+	}
+
+	switch tag.Relation {
+	case RelationHasMany:
+		if f.Type.Kind() != reflect.Slice {
+			return nil, fmt.Errorf("has_many field %s must be a slice, got %s", f.Name, f.Type)
+		}
+		rel.Target = structTarget(f.Type.Elem())
+	case RelationHasOne:
+		rel.Target = structTarget(f.Type)
+	case RelationBelongsTo:
+		if rel.IsPolymorphic() {
+			// The concrete parent type is only known per row
+			if f.Type.Kind() != reflect.Interface {
+				return nil, fmt.Errorf("polymorphic belongs_to field %s must be an interface, got %s", f.Name, f.Type)
+			}
+			return rel, nil
+		}
+		rel.Target = structTarget(f.Type)
+	}
+
+	if rel.Target == nil {
+		return nil, fmt.Errorf("%s field %s must reference a struct, got %s", tag.Relation, f.Name, f.Type)
+	}
+
+	return rel, nil
+}
+
+// structTarget returns the struct type behind t (dereferencing one pointer level),
+// or nil if t does not refer to a struct.
+func structTarget(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	return t
+}
+
+// polymorphicTypes maps discriminator values to struct types and back.
+var polymorphicTypes = struct {
+	mu      sync.RWMutex
+	byValue map[string]reflect.Type
+	byType  map[reflect.Type]string
+}{
+	byValue: make(map[string]reflect.Type),
+	byType:  make(map[reflect.Type]string),
+}
+
+// RegisterPolymorphicType associates a discriminator value with struct type T.
+// The value is written to and matched against polymorphic type columns
+// (e.g., commentable_type). Types that are not registered use their table name.
+//
+// Registration is required for every concrete parent of a polymorphic
+// belongs_to relation, since the Go type cannot be derived from the column value.
+//
+// Example:
+//
+//	schema.RegisterPolymorphicType[Post]("post")
+//	schema.RegisterPolymorphicType[Photo]("photo")
+func RegisterPolymorphicType[T any](value string) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	polymorphicTypes.mu.Lock()
+	defer polymorphicTypes.mu.Unlock()
+
+	if prev, ok := polymorphicTypes.byType[t]; ok {
+		delete(polymorphicTypes.byValue, prev)
+	}
+	polymorphicTypes.byValue[value] = t
+	polymorphicTypes.byType[t] = value
+}
+
+// LookupPolymorphicType returns the struct type registered for a discriminator value.
+func LookupPolymorphicType(value string) (reflect.Type, bool) {
+	polymorphicTypes.mu.RLock()
+	defer polymorphicTypes.mu.RUnlock()
+	t, ok := polymorphicTypes.byValue[value]
+	return t, ok
+}
+
+// PolymorphicValue returns the discriminator value stored in polymorphic type
+// columns for this entity: the registered value, or the table name by default.
+func (m *EntityMeta) PolymorphicValue() string {
+	polymorphicTypes.mu.RLock()
+	defer polymorphicTypes.mu.RUnlock()
+	if v, ok := polymorphicTypes.byType[m.Type]; ok {
+		return v
+	}
+	return m.TableName
+}
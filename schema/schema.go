@@ -6,6 +6,7 @@ import (
 	lru "github.com/hashicorp/golang-lru/v2"
 	"reflect"
 	"sync"
+	"time"
 )
 
 var setterCreators = sync.Map{}
@@ -40,6 +41,8 @@ type Context struct {
 	caseSensitive  bool
 	guaranteeTypes bool
 	validateInDev  bool
+	clock          func() time.Time
+	validate       bool
 
 	// Performance optimization storage
 	entityCache      *lru.Cache[reflect.Type, *EntityMeta]
@@ -49,6 +52,9 @@ type Context struct {
 	// Cache configuration
 	cacheSize int
 	onEvict   func(reflect.Type, *EntityMeta)
+
+	// Table name -> set of columns tagged `sensitive`; survives cache eviction
+	sensitive sync.Map
 }
 
 // Option defines functional options for Context configuration
@@ -92,6 +98,18 @@ func WithEvictionCallback(onEvict func(reflect.Type, *EntityMeta)) Option {
 	return func(ctx *Context) { ctx.onEvict = onEvict }
 }
 
+// WithClock sets the time source used for auto_now and auto_now_add fields.
+// Tests can pass a fixed clock to make timestamps deterministic.
+func WithClock(clock func() time.Time) Option {
+	return func(ctx *Context) { ctx.clock = clock }
+}
+
+// WithValidation enables or disables tag and Validator checks before writes.
+// Disabling skips all validation work in hot paths.
+func WithValidation(enabled bool) Option {
+	return func(ctx *Context) { ctx.validate = enabled }
+}
+
 // New creates a new schema Context with the specified options.
 // Each Context maintains independent configuration and caching,
 // allowing multiple database connections with different schema settings.
@@ -103,6 +121,8 @@ func WithEvictionCallback(onEvict func(reflect.Type, *EntityMeta)) Option {
 //   - GuaranteeTypes: false (safe mode)
 //   - ValidateInDev: true
 //   - CacheSize: 256
+//   - Clock: time.Now
+//   - Validation: true
 //
 // Example:
 //
@@ -127,6 +147,8 @@ func New(options ...Option) *Context {
 		guaranteeTypes: false,
 		validateInDev:  true,
 		cacheSize:      256,
+		clock:          time.Now,
+		validate:       true,
 
 		// Initialize storage
 		precompiledTypes: make(map[reflect.Type]*EntityMeta, 64),
@@ -143,6 +165,39 @@ func New(options ...Option) *Context {
 	return ctx
 }
 
+// IsSensitive reports whether column of table belongs to a field tagged
+// `sensitive`. Only tables of introspected entities are known.
+func (ctx *Context) IsSensitive(table, column string) bool {
+	cols, ok := ctx.sensitive.Load(table)
+	return ok && cols.(map[string]bool)[column]
+}
+
+// HasSensitive reports whether table has any column tagged `sensitive`.
+func (ctx *Context) HasSensitive(table string) bool {
+	_, ok := ctx.sensitive.Load(table)
+	return ok
+}
+
+func (ctx *Context) registerSensitive(meta *EntityMeta) {
+	var cols map[string]bool
+	for _, fm := range meta.Fields {
+		if fm.Tag.Sensitive {
+			if cols == nil {
+				cols = make(map[string]bool)
+			}
+			cols[fm.DBName] = true
+		}
+	}
+	if cols != nil {
+		ctx.sensitive.Store(meta.TableName, cols)
+	}
+}
+
+// Now returns the current time according to the Context's clock.
+func (ctx *Context) Now() time.Time {
+	return ctx.clock()
+}
+
 func (ctx *Context) GetConfiguration() ContextConfig {
 	return ContextConfig{
 		NamingStrategy: ctx.namingStrategy,
@@ -34,10 +34,21 @@ type ParsedTag struct {
 	// Automatic timestamp management
 	AutoNowAdd bool // Set to current time on INSERT only
 	AutoNow    bool // Set to current time on INSERT and UPDATE
+	SoftDelete bool // Deletion timestamp; Delete sets it instead of removing the row
+
+	// Multi-tenancy
+	Tenant bool // Tenant discriminator; queries are filtered and inserts stamped with the current tenant
+
+	// Observability
+	Sensitive bool // Values are redacted from query logs
 
 	// ID generation configuration
 	AutoGenerate bool   // Enable automatic ID generation
 	Generator    string // Specific generator name (uuid, ulid, snowflake, nanoid)
+
+	// Relationship configuration
+	Relation    string // Relation kind (has_many, has_one, belongs_to); field is not a column
+	Polymorphic string // Polymorphic association name (e.g. Commentable -> commentable_type/commentable_id)
 }
 
 // TagParser handles efficient parsing and caching of enorm struct tags.
@@ -87,6 +98,7 @@ func (p *TagParser) SetNamingStrategy(strategy NamingStrategy) {
 //	`db:"primary;unique;not null"`        // Multiple constraints
 //	`db:"type:varchar(255);default:''"`   // Type override with default
 //	`db:"auto_generate;generator:uuid"`   // ID generation
+//	`db:"has_many;polymorphic:Commentable"` // Polymorphic relation
 //	`db:"-"`                              // Skip field entirely
 //
 // Parameters:
@@ -194,8 +206,16 @@ func (p *TagParser) parseFlag(tag *ParsedTag, flag string) error {
 		tag.AutoNowAdd = true
 	case "auto_now":
 		tag.AutoNow = true
+	case "soft_delete":
+		tag.SoftDelete = true
+	case "tenant":
+		tag.Tenant = true
+	case "sensitive":
+		tag.Sensitive = true
 	case "auto_generate", "auto":
 		tag.AutoGenerate = true
+	case RelationHasMany, RelationHasOne, RelationBelongsTo:
+		tag.Relation = flag
 	default:
 		// Ignore unknown flags for forward compatibility
 	}
@@ -227,6 +247,9 @@ func (p *TagParser) parseKeyValue(tag *ParsedTag, key, value string) error {
 		tag.Generator = value
 		tag.AutoGenerate = true
 
+	case "polymorphic", "morph":
+		tag.Polymorphic = value
+
 	case "min_length", "min_len":
 		return p.parseIntValue(value, &tag.MinLength, "min_length")
 
@@ -308,6 +331,16 @@ func (tag *ParsedTag) IsSkipped() bool {
 	return tag.Skip
 }
 
+// IsRelation returns true if this field declares an association instead of a column.
+func (tag *ParsedTag) IsRelation() bool {
+	return tag.Relation != ""
+}
+
+// IsPolymorphic returns true if this field declares a polymorphic association.
+func (tag *ParsedTag) IsPolymorphic() bool {
+	return tag.Polymorphic != ""
+}
+
 // HasValidation returns true if this field has any validation constraints.
 func (tag *ParsedTag) HasValidation() bool {
 	return tag.MinLength != nil || tag.MaxLength != nil || len(tag.Enum) > 0
@@ -36,9 +36,29 @@ type EntityMeta struct {
 	ColumnMap map[string]*FieldMeta // Database column name -> FieldMeta (e.g., "first_name" -> FieldMeta)
 	Columns   []string
 
+	// Primary key fields in declaration order. Fields tagged `primary` form the key;
+	// without any, a field mapped to the "id" column is used. Empty if neither exists.
+	PrimaryKey []*FieldMeta
+
+	// Soft delete marker: a field tagged `soft_delete`, or a nullable time field
+	// mapped to the "deleted_at" column. Nil if the entity is hard-deleted.
+	SoftDelete *FieldMeta
+
+	// Tenant discriminator: a field tagged `tenant`. Nil if the entity is shared
+	// between tenants.
+	Tenant *FieldMeta
+
+	// Lifecycle hooks implemented by *Type, detected and resolved once when
+	// the metadata is built
+	Hooks     Hook
+	hookFuncs [hookCount]hookFunc // indexed by the hook's bit position
+
 	// Additional mappings for flexibility
 	AliasMapping map[string]string // Database column -> Go field name (e.g., "first_name" -> "FirstName")
 
+	// Associations (populated by preloading, never mapped to columns)
+	Relations map[string]*RelationMeta // Go field name -> RelationMeta (e.g., "Comments" -> RelationMeta)
+
 	// Performance optimizations
 	preallocatedScanVals []interface{} // Reusable slice for scan operations to reduce allocations
 	scanValsMu           sync.Mutex    // Protects preallocatedScanVals for thread safety
@@ -142,6 +162,16 @@ type FieldMeta struct {
 	PointerMaker func(unsafe.Pointer) interface{} // The optimized pointer creator
 }
 
+// ValueOf returns the current value of this field in the struct at structPtr.
+func (fm *FieldMeta) ValueOf(structPtr unsafe.Pointer) any {
+	return reflect.NewAt(fm.Type, unsafe.Add(structPtr, fm.Offset)).Elem().Interface()
+}
+
+// IsZeroIn reports whether this field holds its type's zero value in the struct at structPtr.
+func (fm *FieldMeta) IsZeroIn(structPtr unsafe.Pointer) bool {
+	return reflect.NewAt(fm.Type, unsafe.Add(structPtr, fm.Offset)).Elem().IsZero()
+}
+
 func (fm *FieldMeta) buildPointerMaker() {
 	offset := fm.Offset
 	fieldType := fm.Type
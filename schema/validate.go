@@ -0,0 +1,175 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"unicode/utf8"
+	"unsafe"
+)
+
+// Validation rule names reported in ValidationError.Rule.
+const (
+	RuleMinLength = "min_length"
+	RuleMaxLength = "max_length"
+	RuleEnum      = "enum"
+	RuleCustom    = "custom" // Error returned by a Validator that is not a ValidationError
+)
+
+// Validator is implemented by entities with rules that span several fields.
+// Validate runs after the tag rules (min_length, max_length, enum) on every
+// insert and update. Returning ValidationErrors (or a ValidationError) merges
+// the entries with the tag rule failures; any other error is reported as a
+// RuleCustom entry.
+//
+// Example:
+//
+//	func (e *Event) Validate() error {
+//	    if e.EndsAt.Before(e.StartsAt) {
+//	        return schema.ValidationError{Field: "EndsAt", Column: "ends_at", Rule: "after_start", Value: e.EndsAt}
+//	    }
+//	    return nil
+//	}
+type Validator interface {
+	Validate() error
+}
+
+// ValidationError describes a single failed rule.
+type ValidationError struct {
+	Field  string // Go field name (e.g., "Status")
+	Column string // Database column name (e.g., "status")
+	Rule   string // Failed rule (RuleMinLength, RuleMaxLength, RuleEnum, ...)
+	Value  any    // Offending value
+	Detail string // Optional human-readable explanation
+}
+
+func (e ValidationError) Error() string {
+	msg := fmt.Sprintf("%s: %s failed for value %v", e.Field, e.Rule, e.Value)
+	if e.Detail != "" {
+		msg += " (" + e.Detail + ")"
+	}
+	return msg
+}
+
+// ValidationErrors lists every rule that failed for an entity.
+// Use errors.As to inspect the individual entries.
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, ve := range e {
+		msgs[i] = ve.Error()
+	}
+	return "validation failed: " + strings.Join(msgs, "; ")
+}
+
+// Validate checks the tag rules of fields (all fields when none are given) in
+// the struct at structPtr, then calls the entity's Validator if implemented.
+// Returns ValidationErrors on failure and nil when validation is disabled
+// (see WithValidation).
+func (ctx *Context) Validate(meta *EntityMeta, structPtr unsafe.Pointer, fields ...*FieldMeta) error {
+	if !ctx.validate {
+		return nil
+	}
+	if len(fields) == 0 {
+		fields = meta.Fields
+	}
+
+	var errs ValidationErrors
+	for _, fm := range fields {
+		if fm.Tag.HasValidation() {
+			errs = fm.check(reflect.NewAt(fm.Type, unsafe.Add(structPtr, fm.Offset)).Elem(), errs)
+		}
+	}
+
+	if v, ok := reflect.NewAt(meta.Type, structPtr).Interface().(Validator); ok {
+		if err := v.Validate(); err != nil {
+			var list ValidationErrors
+			var single ValidationError
+			switch {
+			case errors.As(err, &list):
+				errs = append(errs, list...)
+			case errors.As(err, &single):
+				errs = append(errs, single)
+			default:
+				errs = append(errs, ValidationError{Field: meta.Name, Rule: RuleCustom, Detail: err.Error()})
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// ValidateValue checks a single value destined for fm's column against the
+// field's tag rules, as used by bulk updates that bypass entities.
+func (ctx *Context) ValidateValue(fm *FieldMeta, value any) error {
+	if !ctx.validate || !fm.Tag.HasValidation() {
+		return nil
+	}
+	if errs := fm.check(reflect.ValueOf(value), nil); len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// check appends the tag rule failures for v to errs. Nil pointers and nil
+// values are not checked, since they represent NULL.
+func (fm *FieldMeta) check(v reflect.Value, errs ValidationErrors) ValidationErrors {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return errs
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return errs
+	}
+
+	tag := fm.Tag
+	fail := func(rule, detail string) {
+		errs = append(errs, ValidationError{
+			Field:  fm.Name,
+			Column: fm.DBName,
+			Rule:   rule,
+			Value:  v.Interface(),
+			Detail: detail,
+		})
+	}
+
+	if tag.MinLength != nil || tag.MaxLength != nil {
+		var n int
+		switch v.Kind() {
+		case reflect.String:
+			n = utf8.RuneCountInString(v.String())
+		case reflect.Slice, reflect.Array, reflect.Map:
+			n = v.Len()
+		default:
+			n = -1 // Length rules do not apply
+		}
+		if n >= 0 && tag.MinLength != nil && n < *tag.MinLength {
+			fail(RuleMinLength, fmt.Sprintf("length %d is below %d", n, *tag.MinLength))
+		}
+		if n >= 0 && tag.MaxLength != nil && n > *tag.MaxLength {
+			fail(RuleMaxLength, fmt.Sprintf("length %d exceeds %d", n, *tag.MaxLength))
+		}
+	}
+
+	if len(tag.Enum) > 0 {
+		var s string
+		if v.Kind() == reflect.String {
+			s = v.String()
+		} else {
+			s = fmt.Sprint(v.Interface())
+		}
+		if !slices.Contains(tag.Enum, s) {
+			fail(RuleEnum, "allowed: "+strings.Join(tag.Enum, ", "))
+		}
+	}
+
+	return errs
+}
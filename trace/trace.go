@@ -0,0 +1,63 @@
+// Package trace defines the span-style instrumentation hooks enorm calls, so
+// that a tracing library such as OpenTelemetry can be plugged in without enorm
+// depending on it. Attribute keys follow the OpenTelemetry database semantic
+// conventions.
+package trace
+
+import "context"
+
+// Attribute keys set on spans.
+const (
+	DBSystem    = "db.system"    // Database system, e.g. "postgresql"
+	DBStatement = "db.statement" // SQL text with placeholders, never argument values
+	DBOperation = "db.operation" // SELECT, INSERT, UPDATE, DELETE, COPY, BEGIN
+	DBSQLTable  = "db.sql.table" // Table the statement targets
+)
+
+// Attribute is a key/value pair attached to a span.
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// String returns a string-valued attribute.
+func String(key, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Tracer starts spans. Implementations adapt it to a tracing library.
+//
+// Example (OpenTelemetry):
+//
+//	type otelTracer struct{ t oteltrace.Tracer }
+//
+//	func (o otelTracer) Start(ctx context.Context, name string, attrs ...trace.Attribute) (context.Context, trace.Span) {
+//	    kv := make([]attribute.KeyValue, len(attrs))
+//	    for i, a := range attrs {
+//	        kv[i] = attribute.String(a.Key, fmt.Sprint(a.Value))
+//	    }
+//	    ctx, span := o.t.Start(ctx, name, oteltrace.WithSpanKind(oteltrace.SpanKindClient), oteltrace.WithAttributes(kv...))
+//	    return ctx, otelSpan{span}
+//	}
+type Tracer interface {
+	// Start begins a span as a child of any span in ctx and returns a context
+	// carrying the new span.
+	Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+}
+
+// Span is an operation started by a Tracer.
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	// RecordError marks the span as failed with err.
+	RecordError(err error)
+	// End completes the span. It is called exactly once.
+	End()
+}
+
+// End records err, if any, on span and ends it.
+func End(span Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+	}
+	span.End()
+}
@@ -1,9 +1,11 @@
 package visitor
 
 import (
+	"fmt"
 	"github.com/Konsultn-Engineering/enorm/ast"
 	"github.com/Konsultn-Engineering/enorm/cache"
 	"github.com/Konsultn-Engineering/enorm/dialect"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -17,18 +19,18 @@ var visitorPool = sync.Pool{
 	},
 }
 
-var argsPool = sync.Pool{
-	New: func() any {
-		return make([]any, 0, 8)
-	},
-}
-
 type SQLVisitor struct {
 	sb      strings.Builder
 	args    []any
 	dialect dialect.Dialect
 	qcache  cache.QueryCache
 	mu      sync.Mutex
+
+	// Column each argument is compared with or assigned to, parallel to args
+	argCols   []string
+	lastCols  []string // argCols of the last Build or Render, owned by the caller or the cache
+	col       string   // Column context for the next value
+	tupleCols []string // Columns matching the items of the next value tuple
 }
 
 func NewSQLVisitor(d dialect.Dialect, q cache.QueryCache) *SQLVisitor {
@@ -47,14 +49,21 @@ func (v *SQLVisitor) GetSB() *strings.Builder {
 func (v *SQLVisitor) Release() {
 	v.dialect = nil
 	v.qcache = nil
-	v.sb.Reset()
-	v.args = v.args[:0]
+	v.resetState()
 	visitorPool.Put(v)
 }
 
 func (v *SQLVisitor) Reset() {
+	v.resetState()
+}
+
+func (v *SQLVisitor) resetState() {
 	v.sb.Reset()
 	v.args = v.args[:0]
+	v.argCols = v.argCols[:0]
+	v.lastCols = nil
+	v.col = ""
+	v.tupleCols = nil
 }
 
 func (v *SQLVisitor) Build(root ast.Node) (string, []any, error) {
@@ -62,44 +71,55 @@ func (v *SQLVisitor) Build(root ast.Node) (string, []any, error) {
 
 	cached, ok := v.qcache.Get(fp)
 	if ok && cached != nil {
+		v.lastCols = cached.ArgsOrder
 		if cached.Args != nil {
 			return cached.SQL, cached.Args, nil
 		}
 		return cached.SQL, nil, nil
 	}
 
-	// 2. Slow path: render and cache
-	v.sb.Reset()
-	v.args = v.args[:0]
+	// 2. Slow path: render and cache. The args are owned by the cache entry
+	// and must never be pooled, or a later Build would overwrite them.
+	sql, args, err := v.Render(root)
+	if err != nil {
+		return "", nil, err
+	}
+
+	v.qcache.Set(fp, sql, args, v.lastCols, "", "")
+	return sql, args, nil
+}
+
+// Render renders root without consulting or filling the query cache. Writes
+// use it: their fingerprints embed the written values, so caching them would
+// grow the cache with every distinct row. The returned args are a copy owned
+// by the caller.
+func (v *SQLVisitor) Render(root ast.Node) (string, []any, error) {
+	v.resetState()
 
 	if err := root.Accept(v); err != nil {
 		return "", nil, err
 	}
 
-	sql := v.sb.String()
-	// Cache both SQL and args - use pooled slice for args copy
 	var argsCopy []any
 	if len(v.args) > 0 {
-		argsCopy = argsPool.Get().([]any)
-		defer func() {
-			if argsCopy != nil {
-				argsPool.Put(argsCopy[:0])
-			}
-		}()
-
-		for cap(argsCopy) < len(v.args) {
-			argsCopy = append(argsCopy, nil)
-		}
-		argsCopy = argsCopy[:len(v.args)]
+		argsCopy = make([]any, len(v.args))
 		copy(argsCopy, v.args)
+		v.lastCols = slices.Clone(v.argCols)
 	}
+	return v.sb.String(), argsCopy, nil
+}
 
-	v.qcache.Set(fp, sql, argsCopy, nil, "", "")
-	return sql, argsCopy, nil
+// ArgColumns returns, for each argument of the statement last built or
+// rendered, the column the argument is compared with or assigned to:
+// "column", or "table.column" when qualified, and "" when unknown (e.g. LIMIT
+// values). The slice may be shared with the query cache and must not be modified.
+func (v *SQLVisitor) ArgColumns() []string {
+	return v.lastCols
 }
 
 func (v *SQLVisitor) Arg(a any) {
 	v.args = append(v.args, a)
+	v.argCols = append(v.argCols, v.col)
 }
 
 func (v *SQLVisitor) VisitSelect(s *ast.SelectStmt) error {
@@ -170,17 +190,217 @@ func (v *SQLVisitor) VisitSelect(s *ast.SelectStmt) error {
 }
 
 func (v *SQLVisitor) VisitInsert(stmt *ast.InsertStmt) error {
-	//TODO implement me
+	//	INSERT INTO table_name (column_list)
+	//	VALUES (value_list) [, ...]
+	//	[ON CONFLICT ... | ON DUPLICATE KEY UPDATE ...]
+	//	[RETURNING column_list]
+
+	v.sb.WriteString("INSERT INTO ")
+	if err := stmt.Table.Accept(v); err != nil {
+		return err
+	}
+
+	v.sb.WriteString(" (")
+	for i, col := range stmt.Columns {
+		if i > 0 {
+			v.sb.WriteString(", ")
+		}
+		v.sb.WriteString(v.dialect.QuoteIdentifier(col))
+	}
+	v.sb.WriteString(") VALUES ")
+
+	for r, row := range stmt.Values {
+		if len(row) != len(stmt.Columns) {
+			return fmt.Errorf("insert row %d has %d values for %d columns", r, len(row), len(stmt.Columns))
+		}
+		if r > 0 {
+			v.sb.WriteString(", ")
+		}
+		v.sb.WriteByte('(')
+		for i, val := range row {
+			if i > 0 {
+				v.sb.WriteString(", ")
+			}
+			v.col = stmt.Columns[i]
+			if err := val.Accept(v); err != nil {
+				return err
+			}
+		}
+		v.sb.WriteByte(')')
+	}
+	v.col = ""
+
+	if stmt.OnConflict != nil {
+		var err error
+		if v.dialect.SupportsOnConflict() {
+			err = v.writeOnConflict(stmt.OnConflict)
+		} else {
+			err = v.writeOnDuplicateKey(stmt.OnConflict, stmt.Columns)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(stmt.Returning) > 0 {
+		if !v.dialect.SupportsReturning() {
+			return fmt.Errorf("dialect does not support RETURNING")
+		}
+		v.sb.WriteString(" RETURNING ")
+		v.writeIdentifiers(stmt.Returning)
+	}
+
+	return nil
+}
+
+// writeOnConflict renders the PostgreSQL upsert clause:
+//
+//	ON CONFLICT ("a") DO UPDATE SET "b" = EXCLUDED."b" [WHERE ...]
+func (v *SQLVisitor) writeOnConflict(c *ast.OnConflictClause) error {
+	v.sb.WriteString(" ON CONFLICT")
+	if c.Constraint != "" {
+		v.sb.WriteString(" ON CONSTRAINT ")
+		v.sb.WriteString(v.dialect.QuoteIdentifier(c.Constraint))
+	} else if len(c.Columns) > 0 {
+		v.sb.WriteString(" (")
+		v.writeIdentifiers(c.Columns)
+		v.sb.WriteByte(')')
+	}
+
+	if c.DoNothing || len(c.Update) == 0 {
+		v.sb.WriteString(" DO NOTHING")
+		return nil
+	}
+
+	if c.Constraint == "" && len(c.Columns) == 0 {
+		return fmt.Errorf("ON CONFLICT DO UPDATE requires conflict columns or a constraint")
+	}
+
+	v.sb.WriteString(" DO UPDATE SET ")
+	for i, col := range c.Update {
+		if i > 0 {
+			v.sb.WriteString(", ")
+		}
+		quoted := v.dialect.QuoteIdentifier(col)
+		v.sb.WriteString(quoted)
+		v.sb.WriteString(" = EXCLUDED.")
+		v.sb.WriteString(quoted)
+	}
+
+	if c.Where != nil {
+		return c.Where.Accept(v)
+	}
+	return nil
+}
+
+// writeOnDuplicateKey renders the MySQL/TiDB upsert clause:
+//
+//	ON DUPLICATE KEY UPDATE `b` = VALUES(`b`)
+//
+// MySQL matches any unique key, so conflict columns are not rendered.
+// DO NOTHING is expressed as a no-op self assignment.
+func (v *SQLVisitor) writeOnDuplicateKey(c *ast.OnConflictClause, insertColumns []string) error {
+	if c.Constraint != "" {
+		return fmt.Errorf("conflict on constraint is not supported by this dialect")
+	}
+	if c.Where != nil {
+		return fmt.Errorf("conditional upsert (DO UPDATE ... WHERE) is not supported by this dialect")
+	}
+
+	v.sb.WriteString(" ON DUPLICATE KEY UPDATE ")
+
+	if c.DoNothing || len(c.Update) == 0 {
+		col := insertColumns[0]
+		if len(c.Columns) > 0 {
+			col = c.Columns[0]
+		}
+		quoted := v.dialect.QuoteIdentifier(col)
+		v.sb.WriteString(quoted)
+		v.sb.WriteString(" = ")
+		v.sb.WriteString(quoted)
+		return nil
+	}
+
+	for i, col := range c.Update {
+		if i > 0 {
+			v.sb.WriteString(", ")
+		}
+		quoted := v.dialect.QuoteIdentifier(col)
+		v.sb.WriteString(quoted)
+		v.sb.WriteString(" = VALUES(")
+		v.sb.WriteString(quoted)
+		v.sb.WriteByte(')')
+	}
 	return nil
 }
 
+func (v *SQLVisitor) writeIdentifiers(names []string) {
+	for i, name := range names {
+		if i > 0 {
+			v.sb.WriteString(", ")
+		}
+		v.sb.WriteString(v.dialect.QuoteIdentifier(name))
+	}
+}
+
 func (v *SQLVisitor) VisitUpdate(stmt *ast.UpdateStmt) error {
-	//TODO implement me
+	//	UPDATE table_name
+	//	SET column = value [, ...]
+	//	[WHERE condition]
+
+	v.sb.WriteString("UPDATE ")
+	if err := stmt.Table.Accept(v); err != nil {
+		return err
+	}
+
+	v.sb.WriteString(" SET ")
+	for i, a := range stmt.Set {
+		if i > 0 {
+			v.sb.WriteString(", ")
+		}
+		v.sb.WriteString(v.dialect.QuoteIdentifier(a.Column))
+		v.sb.WriteString(" = ")
+		v.col = a.Column
+		if err := a.Value.Accept(v); err != nil {
+			return err
+		}
+	}
+	v.col = ""
+
+	if stmt.From != nil {
+		if !v.dialect.SupportsUpdateFrom() {
+			return fmt.Errorf("UPDATE ... FROM is not supported by this dialect")
+		}
+		v.sb.WriteString(" FROM ")
+		if err := stmt.From.Accept(v); err != nil {
+			return err
+		}
+	}
+
+	if stmt.Where != nil {
+		if err := stmt.Where.Accept(v); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 func (v *SQLVisitor) VisitDelete(stmt *ast.DeleteStmt) error {
-	//TODO implement me
+	//	DELETE FROM table_name
+	//	[WHERE condition]
+
+	v.sb.WriteString("DELETE FROM ")
+	if err := stmt.Table.Accept(v); err != nil {
+		return err
+	}
+
+	if stmt.Where != nil {
+		if err := stmt.Where.Accept(v); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -195,6 +415,10 @@ func (v *SQLVisitor) VisitColumn(c *ast.Column) error {
 		v.sb.WriteByte('.')
 	}
 	v.sb.WriteString(v.dialect.QuoteIdentifier(c.Name))
+	v.col = c.Name
+	if c.Table != "" {
+		v.col = c.Table + "." + c.Name
+	}
 
 	if c.Alias != "" && c.Alias != c.Name {
 		v.sb.WriteString(" AS ")
@@ -247,7 +471,13 @@ func (v *SQLVisitor) VisitFunction(function *ast.Function) error {
 
 func (v *SQLVisitor) VisitGroupedExpr(g *ast.GroupedExpr) error {
 	v.sb.WriteByte('(')
-	err := g.Expr.Accept(v)
+	var err error
+	if clause, ok := g.Expr.(*ast.WhereClause); ok {
+		// A grouped condition list renders without the WHERE keyword
+		err = v.writeConditions(clause)
+	} else {
+		err = g.Expr.Accept(v)
+	}
 	v.sb.WriteByte(')')
 	return err
 }
@@ -261,11 +491,27 @@ func (v *SQLVisitor) VisitBinaryExpr(expr *ast.BinaryExpr) error {
 	v.sb.WriteString(expr.Operator)
 	v.sb.WriteByte(' ')
 
-	if err := expr.Right.Accept(v); err != nil {
-		return err
+	// ("a", "b") = ($1, $2): attribute each value to its own column
+	if left, ok := expr.Left.(*ast.Tuple); ok {
+		v.tupleCols = tupleColumns(left)
 	}
+	err := expr.Right.Accept(v)
+	v.tupleCols = nil
+	return err
+}
 
-	return nil
+// tupleColumns returns the column names of a row value such as ("a", "b").
+func tupleColumns(t *ast.Tuple) []string {
+	cols := make([]string, len(t.Items))
+	for i, item := range t.Items {
+		if c, ok := item.(*ast.Column); ok {
+			cols[i] = c.Name
+			if c.Table != "" {
+				cols[i] = c.Table + "." + c.Name
+			}
+		}
+	}
+	return cols
 }
 
 func (v *SQLVisitor) VisitUnaryExpr(expr *ast.UnaryExpr) error {
@@ -290,13 +536,113 @@ func (v *SQLVisitor) VisitSubqueryExpr(s *ast.SubqueryExpr) error {
 	return err
 }
 
+func (v *SQLVisitor) VisitTuple(t *ast.Tuple) error {
+	cols := v.tupleCols
+	v.tupleCols = nil
+
+	v.sb.WriteByte('(')
+	for i, item := range t.Items {
+		if i > 0 {
+			v.sb.WriteString(", ")
+		}
+		if len(cols) == len(t.Items) {
+			if _, nested := item.(*ast.Tuple); nested {
+				// ("a", "b") IN (($1, $2), ($3, $4))
+				v.tupleCols = cols
+			} else {
+				v.col = cols[i]
+			}
+		}
+		if err := item.Accept(v); err != nil {
+			return err
+		}
+	}
+	v.sb.WriteByte(')')
+	return nil
+}
+
+func (v *SQLVisitor) VisitValuesTable(t *ast.ValuesTable) error {
+	//	(VALUES (CAST($1 AS type), ...), (...)) AS alias(col, ...)
+
+	v.sb.WriteString("(VALUES ")
+	for i, row := range t.Rows {
+		if i > 0 {
+			v.sb.WriteString(", ")
+		}
+		v.sb.WriteByte('(')
+		for j, val := range row {
+			if j > 0 {
+				v.sb.WriteString(", ")
+			}
+			cast := i == 0 && j < len(t.Types) && t.Types[j] != ""
+			if j < len(t.Columns) {
+				v.col = t.Columns[j]
+			}
+			if cast {
+				v.sb.WriteString("CAST(")
+			}
+			if err := val.Accept(v); err != nil {
+				return err
+			}
+			if cast {
+				v.sb.WriteString(" AS ")
+				v.sb.WriteString(t.Types[j])
+				v.sb.WriteByte(')')
+			}
+		}
+		v.sb.WriteByte(')')
+	}
+	v.sb.WriteString(") AS ")
+	v.sb.WriteString(v.dialect.QuoteIdentifier(t.Alias))
+	v.sb.WriteByte('(')
+	v.writeIdentifiers(t.Columns)
+	v.sb.WriteByte(')')
+	return nil
+}
+
+func (v *SQLVisitor) VisitCaseExpr(c *ast.CaseExpr) error {
+	//	CASE [operand] WHEN cond THEN result [...] [ELSE result] END
+
+	result := v.col // THEN values belong to the column the CASE is assigned to
+	v.sb.WriteString("CASE")
+	if c.Operand != nil {
+		v.sb.WriteByte(' ')
+		if err := c.Operand.Accept(v); err != nil {
+			return err
+		}
+	}
+	for _, w := range c.Whens {
+		v.sb.WriteString(" WHEN ")
+		if err := w.Cond.Accept(v); err != nil {
+			return err
+		}
+		v.sb.WriteString(" THEN ")
+		v.col = result
+		if err := w.Result.Accept(v); err != nil {
+			return err
+		}
+	}
+	if c.Else != nil {
+		v.sb.WriteString(" ELSE ")
+		if err := c.Else.Accept(v); err != nil {
+			return err
+		}
+	}
+	v.sb.WriteString(" END")
+	return nil
+}
+
 func (v *SQLVisitor) VisitWhereClause(clause *ast.WhereClause) error {
 	if clause == nil || clause.First == nil {
 		return nil
 	}
 
 	v.sb.WriteString(" WHERE ")
+	return v.writeConditions(clause)
+}
 
+// writeConditions renders the conditions of clause joined by their operators.
+func (v *SQLVisitor) writeConditions(clause *ast.WhereClause) error {
 	cond := clause.First
 	first := true
 
@@ -0,0 +1,187 @@
+package visitor
+
+import (
+	"testing"
+
+	"github.com/Konsultn-Engineering/enorm/ast"
+	"github.com/Konsultn-Engineering/enorm/cache"
+	"github.com/Konsultn-Engineering/enorm/dialect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestVisitor(d dialect.Dialect) *SQLVisitor {
+	return NewSQLVisitor(d, cache.NewQueryCache())
+}
+
+func compositeKey(a, b any) ast.Node {
+	return ast.NewBinaryExpr(
+		ast.NewTuple(ast.NewColumn("", "order_id", ""), ast.NewColumn("", "line_no", "")),
+		ast.OpEqual,
+		ast.NewTuple(ast.NewValue(a), ast.NewValue(b)),
+	)
+}
+
+func TestVisitUpdateCompositeKey(t *testing.T) {
+	v := newTestVisitor(dialect.NewPostgresDialect())
+
+	stmt := ast.NewUpdateStmt(ast.NewTable("", "order_lines", ""))
+	stmt.AddSet("qty", ast.NewValue(3))
+	stmt.AddSet("price", ast.NewValue(9.5))
+	stmt.AddWhereCondition(compositeKey(7, 2), ast.OpAnd)
+
+	sql, args, err := v.Build(stmt)
+	require.NoError(t, err)
+	assert.Equal(t, `UPDATE "order_lines" SET "qty" = $1, "price" = $2 WHERE ("order_id", "line_no") = ($3, $4)`, sql)
+	assert.Equal(t, []any{3, 9.5, 7, 2}, args)
+}
+
+func TestVisitDelete(t *testing.T) {
+	tests := []struct {
+		name     string
+		dialect  dialect.Dialect
+		cond     ast.Node
+		expected string
+	}{
+		{
+			name:     "SingleKeyPostgres",
+			dialect:  dialect.NewPostgresDialect(),
+			cond:     ast.NewBinaryExpr(ast.NewColumn("", "id", ""), ast.OpEqual, ast.NewValue(42)),
+			expected: `DELETE FROM "order_lines" WHERE "id" = $1`,
+		},
+		{
+			name:     "CompositeKeyMySQL",
+			dialect:  dialect.NewMySQLDialect(),
+			cond:     compositeKey(7, 2),
+			expected: "DELETE FROM `order_lines` WHERE (`order_id`, `line_no`) = (?, ?)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stmt := ast.NewDeleteStmt(ast.NewTable("", "order_lines", ""))
+			stmt.AddWhereCondition(tt.cond, ast.OpAnd)
+
+			sql, _, err := newTestVisitor(tt.dialect).Build(stmt)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, sql)
+		})
+	}
+}
+
+func TestBuildCachedArgsAreStable(t *testing.T) {
+	v := newTestVisitor(dialect.NewPostgresDialect())
+
+	build := func(id int) []any {
+		stmt := ast.NewDeleteStmt(ast.NewTable("", "users", ""))
+		stmt.AddWhereCondition(ast.NewBinaryExpr(ast.NewColumn("", "id", ""), ast.OpEqual, ast.NewValue(id)), ast.OpAnd)
+		_, args, err := v.Build(stmt)
+		require.NoError(t, err)
+		return args
+	}
+
+	first := build(1)
+	build(2)
+
+	// A later build must not overwrite the args cached for an earlier statement
+	assert.Equal(t, []any{1}, first)
+	assert.Equal(t, []any{1}, build(1))
+}
+
+func TestRenderBypassesCache(t *testing.T) {
+	qc := cache.NewQueryCache()
+	v := NewSQLVisitor(dialect.NewPostgresDialect(), qc)
+
+	stmt := ast.NewUpdateStmt(ast.NewTable("", "users", ""))
+	stmt.AddSet("name", ast.NewValue("a"))
+	stmt.AddWhereCondition(ast.NewBinaryExpr(ast.NewColumn("", "id", ""), ast.OpEqual, ast.NewValue(1)), ast.OpAnd)
+
+	sql, args, err := v.Render(stmt)
+	require.NoError(t, err)
+	assert.Equal(t, `UPDATE "users" SET "name" = $1 WHERE "id" = $2`, sql)
+	assert.Equal(t, []any{"a", 1}, args)
+
+	assert.Equal(t, []string{"name", "id"}, v.ArgColumns())
+	assert.Zero(t, qc.Stats().Entries)
+}
+
+func newUpsertStmt(conflict *ast.OnConflictClause) *ast.InsertStmt {
+	stmt := ast.NewInsertStmt(ast.NewTable("", "users", ""), "email", "name")
+	stmt.AddRow(ast.NewValue("a@example.com"), ast.NewValue("A"))
+	stmt.AddRow(ast.NewValue("b@example.com"), ast.NewValue("B"))
+	stmt.OnConflict = conflict
+	return stmt
+}
+
+func TestVisitInsertUpsert(t *testing.T) {
+	versionCheck := ast.NewWhereClause(ast.NewBinaryExpr(
+		ast.NewColumn("users", "name", ""), ast.OpNotEqual, ast.NewColumn("excluded", "name", ""),
+	), ast.OpAnd)
+
+	tests := []struct {
+		name     string
+		dialect  dialect.Dialect
+		conflict *ast.OnConflictClause
+		expected string
+	}{
+		{
+			name:     "PostgresDoUpdate",
+			dialect:  dialect.NewPostgresDialect(),
+			conflict: &ast.OnConflictClause{Columns: []string{"email"}, Update: []string{"name"}},
+			expected: `INSERT INTO "users" ("email", "name") VALUES ($1, $2), ($3, $4) ON CONFLICT ("email") DO UPDATE SET "name" = EXCLUDED."name"`,
+		},
+		{
+			name:    "PostgresDoUpdateWhere",
+			dialect: dialect.NewPostgresDialect(),
+			conflict: &ast.OnConflictClause{
+				Columns: []string{"email"},
+				Update:  []string{"name"},
+				Where:   &ast.WhereClause{First: versionCheck, Tail: versionCheck},
+			},
+			expected: `INSERT INTO "users" ("email", "name") VALUES ($1, $2), ($3, $4) ON CONFLICT ("email") DO UPDATE SET "name" = EXCLUDED."name" WHERE "users"."name" != "excluded"."name"`,
+		},
+		{
+			name:     "PostgresConstraintDoNothing",
+			dialect:  dialect.NewPostgresDialect(),
+			conflict: &ast.OnConflictClause{Constraint: "users_email_key", DoNothing: true},
+			expected: `INSERT INTO "users" ("email", "name") VALUES ($1, $2), ($3, $4) ON CONFLICT ON CONSTRAINT "users_email_key" DO NOTHING`,
+		},
+		{
+			name:     "MySQLDuplicateKey",
+			dialect:  dialect.NewMySQLDialect(),
+			conflict: &ast.OnConflictClause{Columns: []string{"email"}, Update: []string{"name"}},
+			expected: "INSERT INTO `users` (`email`, `name`) VALUES (?, ?), (?, ?) ON DUPLICATE KEY UPDATE `name` = VALUES(`name`)",
+		},
+		{
+			name:     "TiDBDoNothing",
+			dialect:  dialect.NewTiDBDialect(),
+			conflict: &ast.OnConflictClause{Columns: []string{"email"}, DoNothing: true},
+			expected: "INSERT INTO `users` (`email`, `name`) VALUES (?, ?), (?, ?) ON DUPLICATE KEY UPDATE `email` = `email`",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := newTestVisitor(tt.dialect).Build(newUpsertStmt(tt.conflict))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, sql)
+			assert.Equal(t, []any{"a@example.com", "A", "b@example.com", "B"}, args)
+		})
+	}
+}
+
+func TestVisitInsertUnsupportedConflict(t *testing.T) {
+	stmt := newUpsertStmt(&ast.OnConflictClause{Constraint: "users_email_key", DoNothing: true})
+	_, _, err := newTestVisitor(dialect.NewMySQLDialect()).Build(stmt)
+	assert.Error(t, err)
+}
+
+func TestVisitInsertReturning(t *testing.T) {
+	stmt := ast.NewInsertStmt(ast.NewTable("", "users", ""), "email")
+	stmt.AddRow(ast.NewValue("a@example.com"))
+	stmt.Returning = []string{"id"}
+
+	sql, _, err := newTestVisitor(dialect.NewPostgresDialect()).Build(stmt)
+	require.NoError(t, err)
+	assert.Equal(t, `INSERT INTO "users" ("email") VALUES ($1) RETURNING "id"`, sql)
+}